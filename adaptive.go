@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	adaptiveSmoothing = 0.2 // 이동 평균에 새 응답이 반영되는 비율
+	adaptiveIncrease  = 1.1 // 서버가 여유 있을 때 속도를 올리는 비율
+	adaptiveDecrease  = 0.7 // 서버가 힘들어 할 때 속도를 줄이는 비율
+	adaptiveMaxErrors = 0.1 // 에러 비율이 이보다 높으면 속도를 줄입니다
+)
+
+// 응답 시간의 이동 평균과 에러 비율로 rateLimiter의 속도를 조절합니다.
+// 지금까지 본 가장 빠른 평균 응답 시간을 기준으로, 평균이 기준의 2배를 넘거나 에러가 많아지면
+// 속도를 줄이고, 기준에 가깝게 빠르면 조금씩 올립니다. (AIMD와 비슷한 방식)
+type adaptiveController struct {
+	mu      sync.Mutex
+	limiter *rateLimiter
+
+	minRate float64
+	maxRate float64
+
+	latency   float64 // 응답 시간 이동 평균 (초)
+	best      float64 // 지금까지 가장 낮았던 이동 평균
+	errorRate float64
+}
+
+func (a *adaptiveController) start() {
+	rate := a.limiter.Rate()
+	if rate <= 0 {
+		rate = a.minRate
+	}
+	a.limiter.SetRate(a.clamp(rate))
+}
+
+func (a *adaptiveController) clamp(rate float64) float64 {
+	if rate < a.minRate {
+		return a.minRate
+	}
+	if rate > a.maxRate {
+		return a.maxRate
+	}
+	return rate
+}
+
+func (a *adaptiveController) observe(d time.Duration, failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+	a.errorRate += adaptiveSmoothing * (failure - a.errorRate)
+
+	if !failed {
+		if a.latency == 0 {
+			a.latency = d.Seconds()
+		} else {
+			a.latency += adaptiveSmoothing * (d.Seconds() - a.latency)
+		}
+		if a.best == 0 || a.latency < a.best {
+			a.best = a.latency
+		}
+	}
+
+	rate := a.limiter.Rate()
+	next := rate
+	switch {
+	case failed || a.errorRate > adaptiveMaxErrors || (a.best > 0 && a.latency > a.best*2):
+		next = a.clamp(rate * adaptiveDecrease)
+	case a.latency < a.best*1.2:
+		next = a.clamp(rate * adaptiveIncrease)
+	}
+
+	if next != rate {
+		a.limiter.SetRate(next)
+		if next < rate {
+			fmt.Printf("adaptive: slowing down to %.2f req/s (latency %.0fms, errors %.0f%%)\n", next, a.latency*1000, a.errorRate*100)
+		}
+	}
+}
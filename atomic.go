@@ -0,0 +1,37 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// path와 같은 디렉토리의 임시 파일에 write로 내용을 쓰고, 끝까지 성공했을 때만 path로 이름을 바꿉니다.
+// 중간에 실패하거나 프로세스가 죽어도 path에는 이전에 쓴 온전한 파일이 남아 있습니다.
+func writeFileAtomic(path string, write func(w io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp는 0600으로 만들기 때문에 다른 프로그램도 읽을 수 있도록 0644로 바꿉니다.
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// -audit 파일에 한 줄씩 기록되는 요청 하나의 결과
+type auditEvent struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	Status     int       `json:"status,omitempty"`
+	Bytes      int64     `json:"bytes"`
+	DurationMS int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// 모든 요청을 JSONL로 기록하는 audit log입니다. 사람이 읽는 로그와는 별개로, 어떤 page가 왜 실패했는지
+// 나중에 분석하기 위한 용도입니다. 같은 URL을 다시 요청하면 attempt가 1씩 늘어납니다.
+type auditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	enc      *json.Encoder
+	attempts map[string]int
+}
+
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{file: file, enc: json.NewEncoder(file), attempts: map[string]int{}}, nil
+}
+
+func (a *auditLog) nextAttempt(url string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.attempts[url]++
+	return a.attempts[url]
+}
+
+func (a *auditLog) write(ev auditEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enc.Encode(ev)
+}
+
+func (a *auditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.file.Sync(); err != nil {
+		a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
+
+// 응답 body를 다 읽고 닫을 때 읽은 byte 수와 걸린 시간을 audit log에 기록합니다.
+type auditBody struct {
+	io.ReadCloser
+	log   *auditLog
+	event auditEvent
+	start time.Time
+	once  sync.Once
+}
+
+func (b *auditBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.event.Bytes += int64(n)
+	if err != nil && err != io.EOF && b.event.Error == "" {
+		b.event.Error = err.Error()
+	}
+	return n, err
+}
+
+func (b *auditBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.event.DurationMS = time.Since(b.start).Milliseconds()
+		b.log.write(b.event)
+	})
+	return err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 게시글 행 안에서 badge 아이콘을 찾는 기본 selector. -badge-selector hot=img.icon-hot처럼 바꿀 수 있습니다.
+// image는 썸네일이 붙은 행도 이미지 글로 봅니다.
+var defaultBadgeSelectors = map[string]string{
+	"new":   "td.tit .ico-new",
+	"hot":   "td.tit .ico-hot",
+	"image": "td.tit .ico-img, td.tit span.thumb",
+}
+
+func badgeNames() []string {
+	names := []string{}
+	for name := range defaultBadgeSelectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// "name=selector" 하나를 읽습니다.
+func parseBadgeSelector(spec string) (name, selector string, err error) {
+	name, selector, found := strings.Cut(spec, "=")
+	name, selector = strings.TrimSpace(name), strings.TrimSpace(selector)
+	if !found || name == "" || selector == "" {
+		return "", "", fmt.Errorf("%q is not in name=selector form", spec)
+	}
+	if _, exists := defaultBadgeSelectors[name]; !exists {
+		return "", "", fmt.Errorf("unknown badge %q (expected %s)", name, strings.Join(badgeNames(), ", "))
+	}
+	if err := checkSelector(selector); err != nil {
+		return "", "", fmt.Errorf("%q: invalid selector: %w", spec, err)
+	}
+	return name, selector, nil
+}
+
+// 게시글 행의 badge(new, hot, image)를 is_new, is_hot, has_image 컬럼으로 수집합니다. selectors가 nil이면 수집하지 않습니다.
+// 아이콘이 없는 행은 false입니다.
+func WithBadges(selectors map[string]string) Option {
+	return func(s *Scraper) {
+		s.badges = selectors
+	}
+}
+
+// parsePage가 만든 pages의 row 순서로 게시글 행을 다시 찾아서 badge를 채웁니다.
+func collectBadges(doc *goquery.Document, pages []pageInformation, selectors map[string]string) {
+	if selectors == nil {
+		return
+	}
+	rows := doc.Find(listingRowSelector)
+	for i := range pages {
+		row := rows.Eq(pages[i].row)
+		pages[i].newBadge = row.Find(selectors["new"]).Length() > 0
+		pages[i].hot = row.Find(selectors["hot"]).Length() > 0
+		pages[i].hasImage = row.Find(selectors["image"]).Length() > 0
+	}
+}
+
+// -include-badges의 selector. 기본값 위에 -badge-selector를 덮어씁니다. 꺼져 있으면 nil입니다.
+// Validate를 통과한 설정이라고 가정하고 잘못된 항목은 건너뜁니다.
+func (c Config) badgeSelectors() map[string]string {
+	if !c.IncludeBadges {
+		return nil
+	}
+	selectors := map[string]string{}
+	for name, selector := range defaultBadgeSelectors {
+		selectors[name] = selector
+	}
+	for _, spec := range c.BadgeSelectors {
+		if name, selector, err := parseBadgeSelector(spec); err == nil {
+			selectors[name] = selector
+		}
+	}
+	return selectors
+}
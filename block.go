@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// 로그인 화면, captcha, 차단 안내 page를 알아보는 규칙. 하나라도 걸리면 목록 page가 아니라고 판단합니다.
+type blockRules struct {
+	selectors []string // 이 중 하나라도 page에 있으면 차단 (captcha 요소 등)
+	titles    []string // <title>에 이 중 하나라도 포함되면 차단 (대소문자 무시)
+	urls      []string // 다른 URL로 redirect되었고, 그 URL에 이 중 하나라도 포함되면 차단 (대소문자 무시)
+}
+
+func defaultBlockRules() blockRules {
+	return blockRules{
+		selectors: []string{".g-recaptcha", `iframe[src*="recaptcha"]`, "#challenge-form", "form#captcha"},
+		titles:    []string{"access denied", "attention required", "접근이 제한", "접근 거부", "차단"},
+		urls:      []string{"/member/login", "login.php", "captcha"},
+	}
+}
+
+// 게시판이 요청을 막았을 때의 에러. 재시도해도 소용이 없으므로 나머지 수집도 바로 중단합니다.
+type blockedError struct {
+	url    string
+	reason string
+}
+
+func (e *blockedError) Error() string {
+	return fmt.Sprintf("blocked by the board at %s: %s (slow down with -rps, try another -proxy or wait before retrying; "+
+		"detection rules are set with -block-selector, -block-title and -block-url)", e.url, e.reason)
+}
+
+func (e *blockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+func isBlocked(err error) bool {
+	var blocked *blockedError
+	return errors.As(err, &blocked)
+}
+
+// 받아온 문서가 차단 page인지 확인합니다. doc.Url이 있으면 그 URL이 requested와 다를 때 redirect로 봅니다.
+func (r blockRules) check(requested string, doc *goquery.Document) error {
+	if doc.Url != nil && doc.Url.String() != requested {
+		final := strings.ToLower(doc.Url.String())
+		for _, pattern := range r.urls {
+			if strings.Contains(final, strings.ToLower(pattern)) {
+				return &blockedError{url: requested, reason: fmt.Sprintf("redirected to %s", doc.Url)}
+			}
+		}
+	}
+
+	title := strings.ToLower(strings.TrimSpace(doc.Find("title").First().Text()))
+	for _, pattern := range r.titles {
+		if strings.Contains(title, strings.ToLower(pattern)) {
+			return &blockedError{url: requested, reason: fmt.Sprintf("page title is %q", title)}
+		}
+	}
+
+	for _, selector := range r.selectors {
+		if doc.Find(selector).Length() > 0 {
+			return &blockedError{url: requested, reason: fmt.Sprintf("page contains %s", selector)}
+		}
+	}
+
+	return nil
+}
+
+// 로그인/captcha/차단 page를 알아보는 규칙을 정합니다. 기본은 defaultBlockRules입니다.
+// 셋 다 비우면 확인하지 않습니다.
+func WithBlockRules(selectors, titles, urls []string) Option {
+	return func(s *Scraper) {
+		s.block = blockRules{selectors: selectors, titles: titles, urls: urls}
+	}
+}
+
+func checkSelector(selector string) error {
+	_, err := cascadia.Compile(selector)
+	return err
+}
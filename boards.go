@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// -url 값이 게시판 목록 URL로 쓸 수 있는 절대 http(s) URL인지 확인합니다.
+func checkBaseURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("-url %q is not a valid URL: %v", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("-url %q must be an absolute http(s) URL", raw)
+	}
+	return nil
+}
+
+// -url -일 때 r에서 게시판 URL을 한 줄에 하나씩 읽습니다. 빈 줄과 #으로 시작하는 줄은 건너뜁니다.
+// 잘못된 URL이 있으면 수집을 시작하기 전에 줄 번호와 함께 에러로 돌려줍니다.
+func readBoardURLs(r io.Reader) ([]string, error) {
+	boards := []string{}
+	problems := []string{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := checkBaseURL(text); err != nil {
+			problems = append(problems, fmt.Sprintf("stdin:%d: %v", line, err))
+			continue
+		}
+		boards = append(boards, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid board URLs:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if len(boards) == 0 {
+		return nil, fmt.Errorf("-url -: no board URLs on stdin")
+	}
+	return boards, nil
+}
+
+// 게시판 URL을 디렉토리 이름으로 쓸 수 있게 바꿉니다. host와 path의 영문자, 숫자 외의 글자는 -로 바꿉니다.
+// https://www.inven.co.kr/board/ff14/4337?p= -> www-inven-co-kr-board-ff14-4337
+func boardName(board string) string {
+	u, err := url.Parse(board)
+	if err != nil {
+		return "board"
+	}
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, u.Host+u.Path)
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	return strings.Trim(name, "-")
+}
+
+// -per-board에서 게시판 하나의 결과를 쓸 설정. 출력 경로에 board=<이름> 디렉토리를 넣습니다.
+// -csv-header-comment의 URL도 그 게시판으로 바꿉니다. -snapshot-html 디렉토리는 게시판이 여럿이면 항상 이렇게 나눕니다.
+func (c Config) boardConfig(board string) Config {
+	c.BaseURL = board
+	if c.Output != "" {
+		c.Output = partitionPath(c.Output, "board", boardName(board))
+	} else {
+		c.OutputDir = filepath.Join(c.OutputDir, "board="+boardName(board))
+	}
+	// page 번호가 게시판마다 겹치므로 snapshot은 -per-board가 아니어도 게시판마다 나눠서 저장합니다.
+	if c.SnapshotHTML != "" {
+		c.SnapshotHTML = filepath.Join(c.SnapshotHTML, "board="+boardName(board))
+	}
+	return c
+}
+
+// manifest에 기록할 게시판 목록. 게시판이 하나면 base_url과 같으므로 기록하지 않습니다.
+func boardList(boards []string) []string {
+	if len(boards) < 2 {
+		return nil
+	}
+	return boards
+}
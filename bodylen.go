@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 게시글 page에서 본문이 들어 있는 요소
+const postBodySelector = "#powerbbsContent"
+
+// post processor까지 거친 최종 결과의 게시글 page를 하나씩 받아서 본문 글자 수를 bodyLen에 기록합니다.
+// 본문은 저장하지 않으므로 결과 파일은 컬럼 하나만 커집니다.
+func WithBodyLength(include bool) Option {
+	return func(s *Scraper) {
+		s.bodyLength = include
+	}
+}
+
+// 게시글마다 본문 글자 수를 채웁니다. 요청은 downloadImages처럼 page 수집과 같은 rate limit, 재시도, worker 수를 따르고,
+// 실패한 게시글은 로그만 남기고 bodyLen을 -1로 둡니다.
+func (s *Scraper) collectBodyLengths(ctx context.Context, pages []pageInformation) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := 0
+
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				length, err := s.postBodyLength(ctx, pages[i].link)
+				if err != nil {
+					log.Printf("body of post %d: %v\n", pages[i].pageNum, err)
+					pages[i].bodyLen = -1
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					continue
+				}
+				pages[i].bodyLen = length
+			}
+		}()
+	}
+
+	for i := range pages {
+		if pages[i].link != "" {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failed > 0 {
+		fmt.Printf("%d post bodies could not be fetched\n", failed)
+	}
+}
+
+func (s *Scraper) postBodyLength(ctx context.Context, link string) (int, error) {
+	var doc *goquery.Document
+	err := s.withRetries(ctx, func() error {
+		var err error
+		doc, err = s.fetch(ctx, link)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	body := doc.Find(postBodySelector).First()
+	if body.Length() == 0 {
+		return 0, fmt.Errorf("%s: no %s element in the post page", link, postBodySelector)
+	}
+	return bodyLength(body.Text()), nil
+}
+
+// 공백을 한 칸으로 줄인 본문의 글자(rune) 수
+func bodyLength(text string) int {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,43 @@
+package main
+
+import "strings"
+
+// 제목 앞에 붙은 [질문], 【정보】 같은 말머리를 떼어내서 말머리와 나머지 제목을 리턴합니다.
+// 말머리가 없으면 category는 빈 값이고 rest는 title 그대로입니다.
+func splitCategory(title string) (category, rest string) {
+	trimmed := strings.TrimSpace(title)
+	for _, pair := range [][2]string{{"[", "]"}, {"【", "】"}} {
+		if !strings.HasPrefix(trimmed, pair[0]) {
+			continue
+		}
+		end := strings.Index(trimmed, pair[1])
+		if end < 0 {
+			continue
+		}
+		category = strings.TrimSpace(trimmed[len(pair[0]):end])
+		if category == "" {
+			continue
+		}
+		return category, strings.TrimSpace(trimmed[end+len(pair[1]):])
+	}
+	return "", title
+}
+
+// 별도 요소로 렌더링된 말머리 text에서 괄호를 벗깁니다. ("[잡담]" -> "잡담")
+func cleanCategory(text string) string {
+	text = strings.TrimSpace(text)
+	if category, rest := splitCategory(text); category != "" && rest == "" {
+		return category
+	}
+	return text
+}
+
+// -strip-category 옵션에서 사용하는 post processor. 제목 앞의 말머리를 지웁니다.
+func stripCategoryProcessor(pages []pageInformation) ([]pageInformation, error) {
+	for i := range pages {
+		if category, rest := splitCategory(pages[i].title); category != "" && category == pages[i].category {
+			pages[i].title = rest
+		}
+	}
+	return pages, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// -format parquet처럼 build tag가 붙은 파일에서 등록하는 columnar 형식. (columnar_parquet.go)
+// 텍스트 형식과 달리 -encoding, -bom을 거치지 않고 file에 바로 씁니다.
+var columnarWriters = map[string]func(w io.Writer, pages []pageInformation, cfg Config) error{}
+
+// build tag 없이 빌드하면 등록되지 않는 형식과 필요한 tag
+var formatBuildTags = map[string]string{
+	"parquet": "parquet",
+}
+
+// -format 값 하나가 올바른지 확인합니다.
+func checkFormat(format string) error {
+	switch format {
+	case "csv", "json", "ndjson":
+		return nil
+	}
+	if _, exists := columnarWriters[format]; exists {
+		return nil
+	}
+	if tag, exists := formatBuildTags[format]; exists {
+		return fmt.Errorf("-format %s requires a binary built with -tags %s", format, tag)
+	}
+	return fmt.Errorf("-format %q is not supported (expected csv, json or ndjson)", format)
+}
+
+func isColumnarFormat(format string) bool {
+	_, exists := columnarWriters[format]
+	return exists
+}
+
+// columnar 형식으로 쓰는 컬럼 하나. type은 manifest에 그대로 기록됩니다.
+type schemaColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // int64, boolean, string
+}
+
+// 숫자와 참/거짓인 필드의 타입. 나머지 필드(-extra-field 포함)는 string입니다.
+var columnTypes = map[string]string{
+	"num":       "int64",
+	"view":      "int64",
+	"comments":  "int64",
+	"recommend": "int64",
+	"dup_group": "int64",
+	"body_len":  "int64",
+	"deleted":   "boolean",
+	"new":       "boolean",
+	"is_new":    "boolean",
+	"is_hot":    "boolean",
+	"has_image": "boolean",
+}
+
+// columnar 형식의 schema. CSV와 같은 컬럼을 같은 순서로 씁니다. (-fields, -extra-field를 따름)
+func (c Config) columnSchema() []schemaColumn {
+	columns := []schemaColumn{}
+	for _, f := range c.csvFields() {
+		typ := columnTypes[f.name]
+		if typ == "" {
+			typ = "string"
+		}
+		columns = append(columns, schemaColumn{Name: f.name, Type: typ})
+	}
+	return columns
+}
+
+// manifest에 기록할 schema. columnar 형식을 쓰지 않았으면 nil입니다.
+func (c Config) manifestSchema() []schemaColumn {
+	for _, format := range c.formats() {
+		if isColumnarFormat(format) {
+			return c.columnSchema()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build parquet
+
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func init() {
+	columnarWriters["parquet"] = writeParquet
+}
+
+// -format parquet: columnSchema의 컬럼을 타입이 있는 parquet 컬럼으로 씁니다. 모든 컬럼은 required이고,
+// 공지처럼 값이 없는 숫자는 CSV와 같이 0입니다.
+func writeParquet(w io.Writer, pages []pageInformation, cfg Config) error {
+	columns := cfg.columnSchema()
+	group := parquet.Group{}
+	for _, column := range columns {
+		switch column.Type {
+		case "int64":
+			group[column.Name] = parquet.Int(64)
+		case "boolean":
+			group[column.Name] = parquet.Leaf(parquet.BooleanType)
+		default:
+			group[column.Name] = parquet.String()
+		}
+	}
+	schema := parquet.NewSchema("post", group)
+
+	// parquet.Group은 컬럼을 이름 순서로 정렬하므로, schema의 순서에 맞춰서 row를 만듭니다.
+	fields := map[string]outputField{}
+	for _, f := range cfg.csvFields() {
+		fields[f.name] = f
+	}
+	types := map[string]string{}
+	for _, column := range columns {
+		types[column.Name] = column.Type
+	}
+
+	writer := parquet.NewWriter(w, schema)
+	rows := make([]parquet.Row, 0, len(pages))
+	for _, page := range pages {
+		row := parquet.Row{}
+		for i, field := range schema.Fields() {
+			value := fields[field.Name()].value(page)
+			var v parquet.Value
+			switch types[field.Name()] {
+			case "int64":
+				n, _ := strconv.ParseInt(value, 10, 64)
+				v = parquet.Int64Value(n)
+			case "boolean":
+				b, _ := strconv.ParseBool(value)
+				v = parquet.BooleanValue(b)
+			default:
+				v = parquet.ByteArrayValue([]byte(value))
+			}
+			row = append(row, v.Level(0, 0, i))
+		}
+		rows = append(rows, row)
+	}
+	if _, err := writer.WriteRows(rows); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// 두 결과 파일 사이에서 글쓴이 한 명의 활동 변화 (-compare-users)
+type userDelta struct {
+	user                    string
+	postsBefore, postsAfter int
+	viewsBefore, viewsAfter int
+}
+
+// 이전 파일에는 없고 이번 파일에만 글이 있으면 new, 반대면 silent입니다.
+func (d userDelta) status() string {
+	switch {
+	case d.postsBefore == 0 && d.postsAfter > 0:
+		return "new"
+	case d.postsBefore > 0 && d.postsAfter == 0:
+		return "silent"
+	}
+	return ""
+}
+
+// 글쓴이별로 두 결과의 글 수와 조회수 합계를 비교합니다.
+// 글 수 변화가 큰 글쓴이부터, 같으면 조회수 변화가 큰 순서로 정렬합니다.
+func compareUsers(before, after []pageInformation) []userDelta {
+	byUser := map[string]*userDelta{}
+	get := func(user string) *userDelta {
+		d, exists := byUser[user]
+		if !exists {
+			d = &userDelta{user: user}
+			byUser[user] = d
+		}
+		return d
+	}
+	for _, page := range before {
+		d := get(page.user)
+		d.postsBefore++
+		d.viewsBefore += page.view
+	}
+	for _, page := range after {
+		d := get(page.user)
+		d.postsAfter++
+		d.viewsAfter += page.view
+	}
+
+	deltas := []userDelta{}
+	for _, d := range byUser {
+		deltas = append(deltas, *d)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		a, b := deltas[i], deltas[j]
+		if da, db := abs(a.postsAfter-a.postsBefore), abs(b.postsAfter-b.postsBefore); da != db {
+			return da > db
+		}
+		if da, db := abs(a.viewsAfter-a.viewsBefore), abs(b.viewsAfter-b.viewsBefore); da != db {
+			return da > db
+		}
+		return a.user < b.user
+	})
+	return deltas
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+var compareUsersHeader = []string{"user", "posts_before", "posts_after", "posts_delta", "views_before", "views_after", "views_delta", "status"}
+
+func (d userDelta) record() []string {
+	return []string{
+		d.user,
+		strconv.Itoa(d.postsBefore), strconv.Itoa(d.postsAfter), fmt.Sprintf("%+d", d.postsAfter-d.postsBefore),
+		strconv.Itoa(d.viewsBefore), strconv.Itoa(d.viewsAfter), fmt.Sprintf("%+d", d.viewsAfter-d.viewsBefore),
+		d.status(),
+	}
+}
+
+// -compare-users: 두 결과 파일을 읽어서 글쓴이별 변화를 -o가 있으면 CSV로 쓰고, 없으면 표로 출력합니다.
+func runCompareUsers(cfg Config) error {
+	before, err := readExport(cfg.CompareUsers[0], cfg.Encoding)
+	if err != nil {
+		return err
+	}
+	after, err := readExport(cfg.CompareUsers[1], cfg.Encoding)
+	if err != nil {
+		return err
+	}
+
+	deltas := compareUsers(before, after)
+	rows := [][]string{compareUsersHeader}
+	for _, d := range deltas {
+		rows = append(rows, d.record())
+	}
+
+	if cfg.Output != "" {
+		return writeFileAtomic(cfg.Output, func(w io.Writer) error {
+			out := csv.NewWriter(w)
+			out.WriteAll(rows)
+			return out.Error()
+		})
+	}
+	tableWidth, color := terminalInfo(cfg)
+	writeRows(os.Stdout, rows, tableWidth, color)
+
+	newUsers, silentUsers := 0, 0
+	for _, d := range deltas {
+		switch d.status() {
+		case "new":
+			newUsers++
+		case "silent":
+			silentUsers++
+		}
+	}
+	fmt.Printf("%d users: %d newly active, %d newly silent\n", len(deltas), newUsers, silentUsers)
+	return nil
+}
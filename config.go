@@ -0,0 +1,1202 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 여러 번 줄 수 있는 옵션 (-category a -category b)
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// 명령행에서 stringList를 받는 flag.Value. 설정 파일에서 읽은 목록에 덧붙이지 않고,
+// 명령행에서 처음 값을 받을 때 목록을 새로 시작해서 명령행 값이 우선하게 합니다.
+type listFlag struct {
+	list *stringList
+	set  bool
+}
+
+func (f *listFlag) String() string {
+	if f.list == nil {
+		return ""
+	}
+	return f.list.String()
+}
+
+func (f *listFlag) Set(value string) error {
+	if !f.set {
+		*f.list = nil
+		f.set = true
+	}
+	return f.list.Set(value)
+}
+
+// 쉼표로 구분한 목록을 받는 flag.Value (-fields num,title,view). 명령행 값이 설정 파일의 목록을 대체합니다.
+type commaListFlag struct {
+	list *stringList
+}
+
+func (f *commaListFlag) String() string {
+	if f.list == nil {
+		return ""
+	}
+	return f.list.String()
+}
+
+func (f *commaListFlag) Set(value string) error {
+	*f.list = nil
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			*f.list = append(*f.list, name)
+		}
+	}
+	return nil
+}
+
+// 시간을 "60s", "1m30s"처럼 받는 flag.Value. 설정 파일에서도 같은 형식의 문자열로 씁니다.
+type duration time.Duration
+
+func (d *duration) String() string {
+	return time.Duration(*d).String()
+}
+
+func (d *duration) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"60s\": %w", err)
+	}
+	return d.Set(value)
+}
+
+const defaultBaseURL = "https://www.inven.co.kr/board/ff14/4337?p="
+
+// Config는 명령행 옵션과 설정 파일로 정해지는 실행 설정입니다.
+// JSON 키는 명령행 옵션 이름과 같습니다.
+type Config struct {
+	// "-"이면 stdin에서 게시판 URL을 한 줄에 하나씩 읽어서 차례로 수집합니다.
+	// PerBoard면 게시판마다 board=<이름> 디렉토리에 따로 쓰고, 아니면 한 파일로 합쳐서 씁니다.
+	BaseURL  string `json:"url"`
+	PerBoard bool   `json:"per-board"`
+
+	// page URL의 형식 ("{base}/page/{n}"). 비어 있으면 BaseURL의 query에 page 번호를 넣습니다.
+	PageTemplate string `json:"page-template"`
+
+	// 목록 page를 HTML 대신 읽을 JSON endpoint의 URL 형식 ("{base}/api/list?page={n}")과, 응답에서 게시글 배열이 있는 key
+	JSONEndpoint string `json:"json-endpoint"`
+	JSONListKey  string `json:"json-list-key"`
+
+	// CPU profile, heap profile을 쓸 파일과 pprof HTTP 서버의 주소. 비어 있으면 사용하지 않습니다.
+	CPUProfile string `json:"prof"`
+	MemProfile string `json:"memprof"`
+	PprofAddr  string `json:"pprof-addr"`
+
+	// -interval로 반복할 때 /healthz, /readyz, /lastrun을 제공할 주소. PprofAddr와 같으면 그 server를 같이 씁니다.
+	HealthAddr string `json:"health-addr"`
+
+	// 버전 정보만 출력하고 종료
+	PrintVersion bool `json:"-"`
+
+	// page별 URL이 어떻게 만들어지는지만 출력하고 종료
+	PrintURLTemplate bool `json:"-"`
+
+	// 자세한 진단 정보를 log로 남깁니다.
+	Verbose bool `json:"v"`
+
+	// 두 결과 파일("이전,이번")의 글쓴이별 변화만 출력하고 종료
+	CompareUsers stringList `json:"-"`
+
+	// 수집할 page가 ConfirmPages보다 많으면 터미널에서 실행할 때 시작하기 전에 물어봅니다. Yes면 묻지 않습니다.
+	ConfirmPages int  `json:"confirm-pages"`
+	Yes          bool `json:"yes"`
+
+	// 이 page 하나만 가져와서 selector마다 찾은 요소를 출력하고 종료 (0이면 사용하지 않음)
+	Explain int `json:"-"`
+
+	// 게시판 URL이 HTML을 돌려주는지만 확인하고 종료
+	CheckOnly bool `json:"-"`
+
+	// 로그인/captcha/차단 page를 알아보는 규칙 (기본값은 defaultBlockRules). 명령행에서 주면 기본값을 대체합니다.
+	BlockSelectors stringList `json:"block-selector"`
+	BlockTitles    stringList `json:"block-title"`
+	BlockURLs      stringList `json:"block-url"`
+
+	// 요청을 번갈아 보낼 proxy 목록. 시작할 때 확인해서 응답하지 않는 proxy는 빼고 사용합니다.
+	Proxies stringList `json:"proxy"`
+
+	// proxy 확인 결과만 출력하고 종료
+	ProxyTest bool `json:"-"`
+
+	// 한 요청에서 따라갈 최대 redirect 수. NoRedirects면 redirect를 따라가지 않고 그 page를 실패로 처리합니다.
+	MaxRedirects int  `json:"max-redirects"`
+	NoRedirects  bool `json:"no-redirects"`
+
+	// 모든 요청의 Accept-Language header. 비우면 보내지 않습니다.
+	AcceptLanguage string `json:"accept-language"`
+
+	// 모든 요청에 추가로 보낼 header ("X-Requested-With: XMLHttpRequest"). 같은 이름이면 다른 옵션보다 우선합니다.
+	RequestHeaders stringList `json:"header"`
+
+	// 목록 page를 headless Chrome으로 렌더링해서 가져옵니다. (-tags chromedp로 빌드해야 사용 가능)
+	Render bool `json:"render"`
+
+	// 수집한 게시글을 page마다 내보낼 곳 ("kafka:broker:9092/topic", "file:posts.ndjson"). 파일 출력은 그대로 합니다.
+	Sink string `json:"sink"`
+
+	// file sink에 쓴 게시글을 디스크로 내보내는 주기. 둘 다 0이면 page마다 내보냅니다.
+	SinkFlushRecords  int      `json:"sink-flush-records"`
+	SinkFlushInterval duration `json:"sink-flush-interval"`
+
+	// 실행 정보(버전, 시간, 행 수)를 JSON으로 기록할 파일. 비어 있으면 쓰지 않습니다.
+	Manifest string `json:"manifest"`
+
+	// 받은 목록 page의 HTML을 page 번호로 gzip해서 저장할 디렉토리. 비어 있으면 저장하지 않습니다.
+	SnapshotHTML string `json:"snapshot-html"`
+
+	// 게시판에 요청하지 않고 -snapshot-html로 저장한 디렉토리의 HTML을 다시 파싱합니다.
+	FromSnapshots string `json:"from-snapshots"`
+
+	// 모든 요청을 한 줄에 하나씩 JSON으로 기록할 파일. 비어 있으면 기록하지 않습니다.
+	Audit string `json:"audit"`
+
+	// 수집할 page 범위 (To가 0이면 마지막 page까지)
+	From int `json:"from"`
+	To   int `json:"to"`
+
+	// 발견한 page 수와 상관없이 수집할 page 수의 상한 (0이면 제한 없음). 범위와 함께 주면 더 좁은 쪽이 적용됩니다.
+	MaxPages int `json:"max-pages"`
+
+	// 마지막 page를 찾지 않고 첫 page만 수집합니다.
+	FirstPageOnly bool `json:"first-page-only"`
+
+	// 목록 page 하나의 게시글 수(공지 제외). 0이면 첫 page에서 세어서 마지막 page를 짐작할 때 사용합니다.
+	PostsPerPage int `json:"posts-per-page"`
+
+	// 수집할 목록. normal(전체 목록) 또는 recommended(추천글 목록)
+	Mode string `json:"mode"`
+
+	// 게시판이 정해진 순서(recommend, views, recent)로 정렬한 목록을 받아서 그 순서대로 출력합니다.
+	ServerSort string `json:"server-sort"`
+
+	// 결과의 순서. num(기본, 게시글 번호 순서) 또는 original(게시판에 보이는 순서: page 순서, page 안의 행 순서)
+	Sort string `json:"sort"`
+
+	// 목록 앞에서부터 게시글 Limit개만 수집합니다. 필요한 page만 요청합니다. (0이면 제한 없음)
+	Limit int `json:"limit"`
+
+	// 전체 page 중 일부만 무작위로 골라서 수집합니다. Sample은 "10%" 또는 "0.1" 형태입니다.
+	Sample  string `json:"sample"`
+	SampleN int    `json:"sample-n"`
+
+	// 무작위로 동작하는 기능(-sample, proxy 순서) 전체의 seed. 0이면 실행마다 새로 정합니다.
+	Seed int64 `json:"seed"`
+
+	// 이전 실행에서 본 게시글 번호를 기록하는 파일. 없던 게시글은 New 컬럼으로 표시하고, 결과를 쓴 뒤 파일에 추가합니다.
+	// SeenReset이면 파일의 기존 기록을 무시하고 새로 시작합니다.
+	SeenDB    string `json:"seen-db"`
+	SeenReset bool   `json:"seen-reset"`
+
+	// 지금까지 쓴 가장 큰 게시글 번호를 기록하는 파일. 이번 실행에서는 그보다 번호가 큰 게시글만 출력하고,
+	// 결과를 쓴 뒤 기록을 올립니다. 파일이 없으면 전부 출력합니다.
+	Watermark string `json:"watermark"`
+
+	// 요청 속도 제한: RPS가 0이면 제한 없이 요청합니다.
+	Workers int     `json:"workers"`
+	RPS     float64 `json:"rps"`
+
+	// host 하나에 동시에 보내는 요청 수의 상한. 0이면 -workers만큼 보낼 수 있습니다.
+	MaxPerHost int `json:"max-per-host"`
+
+	// worker가 page 하나를 끝낼 때마다 쉬는 시간. rate limit과 함께 적용되므로 둘 중 느린 쪽이 속도를 정합니다.
+	SleepBetweenPages duration `json:"sleep-between-pages"`
+
+	// 응답 시간에 따라 요청 속도를 MinRPS ~ MaxRPS 사이에서 자동으로 조절합니다.
+	Adaptive bool    `json:"adaptive"`
+	MinRPS   float64 `json:"min-rps"`
+	MaxRPS   float64 `json:"max-rps"`
+
+	// 최근 ThrottleWindow개 요청 중 실패 비율이 ThrottleThreshold를 넘으면 RPS에 ThrottleFactor를 곱해서 줄이고,
+	// 요청이 성공할 때마다 ThrottleRecover를 곱해서 RPS까지 되돌립니다.
+	ThrottleOnError   bool    `json:"throttle-on-error"`
+	ThrottleThreshold float64 `json:"throttle-threshold"`
+	ThrottleWindow    int     `json:"throttle-window"`
+	ThrottleFactor    float64 `json:"throttle-factor"`
+	ThrottleRecover   float64 `json:"throttle-recover"`
+	ThrottleMinRPS    float64 `json:"throttle-min-rps"`
+
+	// 제목과 글쓴이의 연속된 공백을 하나로 합칩니다. StripInvisible이면 zero-width, 방향 제어 문자도 지웁니다.
+	Normalize      bool `json:"normalize"`
+	StripInvisible bool `json:"strip-invisible"`
+
+	// 제목과 글쓴이를 NFC로 바꿔서 자모가 나뉜(NFD) 한글도 같은 문자열로 다룹니다.
+	NormalizeUnicode bool `json:"normalize-unicode"`
+
+	// 제목이 같은(정규화 기준) 게시글을 찾아 Dup Group 컬럼으로 표시할지 여부
+	DupTitles bool `json:"dup-titles"`
+
+	// 수집 결과를 파일로 쓰기 전에 거쳐갈 외부 명령 (JSON 배열을 stdin/stdout으로 주고받음)
+	PostProcess string `json:"post-process"`
+
+	// page 수집이 실패했을 때의 정책. FailFast면 처음 실패에서 중단하고, 기본(KeepGoing)은 나머지를 계속 수집합니다.
+	FailFast  bool `json:"fail-fast"`
+	KeepGoing bool `json:"keep-going"`
+
+	// 실패한 page가 MaxFailuresAbort개를 넘으면 수집을 중단하고, 그때까지의 결과를 쓴 뒤 에러로 종료합니다. 0이면 제한하지 않습니다.
+	MaxFailuresAbort int `json:"max-failures-abort"`
+
+	// keep-going으로 끝난 뒤 실패한 page만 최대 RetryFailures번 더 수집합니다.
+	RetryFailures int `json:"retry-failures"`
+
+	// page 하나에 쓰는 시간의 상한 (재시도 포함). 넘으면 그 page는 실패로 처리합니다. 0이면 제한하지 않습니다.
+	TimeoutPerPage duration `json:"timeout-per-page"`
+
+	// 수집 한 번(게시판 전체)에 쓰는 시간의 상한. 넘으면 그때까지의 결과를 쓰고 종료합니다. 0이면 제한하지 않습니다.
+	Timeout duration `json:"timeout"`
+
+	// 0이 아니면 수집과 결과 쓰기를 Interval마다 반복합니다. (중단할 때까지)
+	// KeepWarm이면 실행 사이에 board host로의 연결을 유지해서 다음 실행의 연결 비용을 줄입니다.
+	Interval duration `json:"interval"`
+	KeepWarm bool     `json:"keep-warm"`
+
+	// Interval을 실행마다 ±IntervalJitter("20%" 또는 "0.2")만큼 무작위로 바꿉니다. 난수는 Seed를 따릅니다.
+	IntervalJitter string `json:"interval-jitter"`
+
+	// 수집이 끝난 뒤 게시글 수, 조회수/댓글/추천 합계를 출력합니다.
+	Stats bool `json:"stats"`
+
+	// 수집한 번호 범위 안에서 빠진 게시글 번호 구간을 출력합니다. ReportGapsFile이 있으면 그 파일에 CSV로 씁니다.
+	ReportGaps     bool   `json:"report-gaps"`
+	ReportGapsFile string `json:"report-gaps-file"`
+
+	// 결과가 0건이어도 exit code 0으로 종료합니다. (기본은 exitNoResults)
+	QuietOnEmpty bool `json:"quiet-on-empty"`
+
+	// 출력 형식(csv, json, ndjson)과 파일 경로. Output이 비어 있으면 pages.<format>에 씁니다.
+	// Format에 "csv,json"처럼 여러 형식을 주면 형식마다 OutputDir/pages.<format>에 씁니다.
+	Format    string `json:"format"`
+	Output    string `json:"o"`
+	OutputDir string `json:"output-dir"`
+
+	// 결과를 이 컬럼 값별 디렉토리(date=2024-05-01/pages.csv)에 나눠서 씁니다. 지금은 date만 지원합니다.
+	PartitionBy string `json:"partition-by"`
+
+	// CSV 값 안의 줄바꿈을 공백으로 바꿉니다. CRLF면 CSV의 행을 \r\n으로 끝냅니다. (기본은 \n)
+	StripNewlines bool `json:"strip-newlines"`
+	CRLF          bool `json:"crlf"`
+
+	// CSV 헤더 앞에 게시판 URL, 수집 시각, 버전, 행 수를 # 주석 줄로 씁니다. #을 건너뛰지 못하는 도구를 위해 기본은 꺼져 있습니다.
+	CSVHeaderComment bool `json:"csv-header-comment"`
+
+	// 결과 파일을 쓴 뒤 다시 읽어서, 행 수와 값이 쓴 그대로인지 확인합니다.
+	ValidateOutput bool `json:"validate-output"`
+
+	// -csv-header-comment에 쓰는 수집 시작 시각. main에서 정합니다.
+	startedAt time.Time
+
+	// -format json 출력을 들여쓰기 없이 씁니다. (ndjson은 항상 한 줄에 하나)
+	Compact bool `json:"compact"`
+
+	// 게시글 행에서 추가로 수집할 필드 ("name=selector" 또는 "name=selector@attr"). 준 순서대로 컬럼이 붙습니다.
+	ExtraFields stringList `json:"extra-field"`
+
+	// CSV와 -pretty-table에 쓸 컬럼을 직접 고릅니다. ("num,title,view") 비어 있으면 기본 컬럼과 켜진 옵션의 컬럼을 씁니다.
+	Fields stringList `json:"fields"`
+
+	// 값이 있어야 하는 필드 ("title,link,num"). 하나라도 비어 있는 행은 빼고 parse 경고로 남깁니다. (FailFast면 중단)
+	RequireFields stringList `json:"require-fields"`
+
+	// 결과를 터미널에 표로도 출력합니다. Top이 0보다 크면 앞에서부터 Top개만 출력합니다.
+	PrettyTable bool `json:"pretty-table"`
+	Top         int  `json:"top"`
+
+	// 표를 이 폭에 맞춰 자릅니다. 0이면 터미널의 폭을 쓰고, 터미널이 아니면 자르지 않습니다.
+	TableWidth int `json:"table-width"`
+
+	// 표 같은 터미널 출력에 색을 쓸지 정합니다. (auto, always, never) NoColor는 never와 같습니다.
+	// auto는 stdout이 터미널이고 NO_COLOR 환경 변수가 비어 있을 때만 색을 씁니다.
+	Color   string `json:"color"`
+	NoColor bool   `json:"no-color"`
+
+	// 게시글의 썸네일 이미지 URL을 Thumbnail 컬럼으로 출력합니다.
+	Thumbnails bool `json:"thumbnails"`
+
+	// 목록의 new, hot, 이미지 아이콘을 is_new, is_hot, has_image 컬럼으로 출력합니다. BadgeSelectors("hot=img.icon-hot")로 selector를 바꿉니다.
+	IncludeBadges  bool       `json:"include-badges"`
+	BadgeSelectors stringList `json:"badge-selector"`
+
+	// 말머리를 Category 컬럼으로 출력합니다. StripCategory면 제목 앞의 [말머리]를 지웁니다.
+	Categories    bool `json:"categories"`
+	StripCategory bool `json:"strip-category"`
+
+	// 말머리가 이 중 하나인 게시글만 남깁니다. (대소문자 무시, 여러 개면 OR)
+	CategoryFilter stringList `json:"category"`
+
+	// 제목 키워드 필터: Match 중 하나라도 포함된 글만 남기고, Exclude 중 하나라도 포함된 글은 지웁니다.
+	// 둘 다 해당되면 Exclude가 우선합니다. Regex면 키워드를 정규식으로 취급합니다. (대소문자 무시)
+	Match   stringList `json:"match"`
+	Exclude stringList `json:"exclude"`
+	Regex   bool       `json:"regex"`
+
+	// 등록일을 CSV 컬럼으로 출력합니다. ("5분 전" 같은 상대 시간은 수집을 시작한 시간 기준으로 바꿉니다)
+	Dates bool `json:"dates"`
+
+	// 댓글 수, 추천 수를 CSV 컬럼으로 출력합니다.
+	Counts bool `json:"counts"`
+
+	// 번호 칸이 숫자가 아닌 게시글("공지")의 원래 text를 Raw No. 컬럼으로 출력합니다.
+	IncludeRawNum bool `json:"include-raw-num"`
+
+	// 조회수/댓글 수/추천 수가 각각 이 값 이상인 게시글만 남깁니다. (0이면 적용하지 않음, 여러 개면 AND)
+	MinViews     int `json:"min-views"`
+	MinComments  int `json:"min-comments"`
+	MinRecommend int `json:"min-recommend"`
+
+	// 여러 page에서 수집된 같은 게시글을 하나로 합칩니다.
+	Dedup bool `json:"dedup"`
+	// 같은 게시글인지 구분할 필드를 +로 이은 것. (num, num+category, link) 비워두면 번호로, 번호가 없는 글은 링크로 구분합니다.
+	DedupKey string `json:"dedup-key"`
+
+	// 마지막 page가 아닌데 행이 MinRows개보다 적게 파싱된 page를 MinRowsAction(warn, retry, skip)으로 처리합니다. (0이면 확인하지 않음)
+	MinRows       int    `json:"min-rows"`
+	MinRowsAction string `json:"min-rows-action"`
+
+	// 게시글 행은 있는데 조회수 칸을 하나도 찾지 못한 page의 처리 (warn, skip)
+	MissingViewAction string `json:"missing-view-action"`
+
+	// 제목 링크가 없는 행(광고 등)을 건너뛰지 않고 빈 제목으로 남깁니다.
+	KeepEmpty bool `json:"keep-empty"`
+
+	// 삭제된 게시글을 건너뛰지 않고 Deleted 컬럼으로 표시합니다.
+	IncludeDeleted bool `json:"include-deleted"`
+
+	// 썸네일을 내려받을 디렉토리. 비어 있으면 내려받지 않습니다.
+	DownloadImages string `json:"download-images"`
+
+	// 수집이 끝난 뒤 게시글 page를 하나씩 받아서 본문 글자 수를 body_len 컬럼에 씁니다.
+	IncludeBodyLength bool `json:"include-body-length"`
+
+	// 출력 파일 인코딩: 기본은 BOM 없는 UTF-8
+	BOM      bool   `json:"bom"`
+	Encoding string `json:"encoding"`
+
+	// CSV 헤더: Lang(en, ko)으로 기본 헤더 모음을 고르고, Headers로 필드별 헤더를 덮어씁니다.
+	Lang    string    `json:"lang"`
+	Headers headerMap `json:"headers"`
+}
+
+func defaultConfig() Config {
+	block := defaultBlockRules()
+	return Config{
+		BaseURL:           defaultBaseURL,
+		BlockSelectors:    block.selectors,
+		BlockTitles:       block.titles,
+		BlockURLs:         block.urls,
+		From:              1,
+		Workers:           8,
+		Dedup:             true,
+		Mode:              "normal",
+		Color:             "auto",
+		Sort:              "num",
+		MinRowsAction:     "warn",
+		MissingViewAction: "warn",
+		AcceptLanguage:    defaultAcceptLanguage,
+		MaxRedirects:      defaultMaxRedirects,
+		ConfirmPages:      defaultConfirmPages,
+		MinRPS:            0.5,
+		MaxRPS:            20,
+		ThrottleThreshold: 0.2,
+		ThrottleWindow:    20,
+		ThrottleFactor:    0.5,
+		ThrottleRecover:   1.05,
+		ThrottleMinRPS:    0.1,
+		Format:            "csv",
+		Encoding:          "utf-8",
+		Lang:              "en",
+	}
+}
+
+// 명령행 옵션을 c의 필드에 연결합니다. 옵션의 기본값은 c의 현재 값입니다.
+func (c *Config) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.BaseURL, "url", c.BaseURL, "board listing URL; the page number is appended to it (- reads one URL per line from stdin)")
+	fs.BoolVar(&c.PerBoard, "per-board", c.PerBoard, "with -url -, write each board into its own board=<name> directory instead of one combined file")
+	fs.StringVar(&c.PageTemplate, "page-template", c.PageTemplate, "page URL format for boards that paginate by path, e.g. {base}/page/{n}; {base} is -url without its query")
+	fs.StringVar(&c.JSONEndpoint, "json-endpoint", c.JSONEndpoint, "read each listing page from this JSON URL instead of the HTML, e.g. {base}/api/list?page={n}; the last page is still found from the HTML")
+	fs.StringVar(&c.JSONListKey, "json-list-key", c.JSONListKey, "dotted key of the post array in a -json-endpoint response, e.g. data.list (empty means the response is the array)")
+	fs.StringVar(&c.CPUProfile, "prof", c.CPUProfile, "write a CPU profile of the run to this file")
+	fs.StringVar(&c.MemProfile, "memprof", c.MemProfile, "write a heap profile to this file when the run ends")
+	fs.StringVar(&c.PprofAddr, "pprof-addr", c.PprofAddr, "serve live pprof profiles on this address, e.g. localhost:6060")
+	fs.StringVar(&c.HealthAddr, "health-addr", c.HealthAddr, "with -interval, serve /healthz, /readyz and /lastrun on this address, e.g. :8080 (may be the same as -pprof-addr)")
+	fs.BoolVar(&c.PrintVersion, "version", c.PrintVersion, "print version information and exit")
+	fs.Var(&commaListFlag{list: &c.CompareUsers}, "compare-users", "compare per-user post counts and views between two exports `previous,current`, then exit (CSV to -o, otherwise a table)")
+	fs.BoolVar(&c.PrintURLTemplate, "print-url-template", c.PrintURLTemplate, "print the URLs requested for the first and last page, then exit")
+	fs.BoolVar(&c.Verbose, "v", c.Verbose, "verbose logging, e.g. the first bytes of responses that are not HTML")
+	fs.IntVar(&c.ConfirmPages, "confirm-pages", c.ConfirmPages, "when run from a terminal, ask before scraping more than this many pages")
+	fs.BoolVar(&c.Yes, "yes", c.Yes, "do not ask before large scrapes")
+	fs.IntVar(&c.Explain, "explain", c.Explain, "fetch this one `page`, print what each selector matched and exit without writing output")
+	fs.BoolVar(&c.CheckOnly, "check", c.CheckOnly, "only check that the board URL responds with 200 HTML, then exit")
+	fs.Var(&listFlag{list: &c.BlockSelectors}, "block-selector", "CSS selector that marks a captcha/ban page; the run stops when a page matches (repeatable, replaces the defaults)")
+	fs.Var(&listFlag{list: &c.BlockTitles}, "block-title", "page title text that marks an access-denied page (repeatable, case-insensitive, replaces the defaults)")
+	fs.Var(&listFlag{list: &c.BlockURLs}, "block-url", "URL text that marks a redirect to a login or captcha page (repeatable, case-insensitive, replaces the defaults)")
+	fs.IntVar(&c.MaxRedirects, "max-redirects", c.MaxRedirects, "follow at most this many redirects per request")
+	fs.BoolVar(&c.NoRedirects, "no-redirects", c.NoRedirects, "do not follow redirects; a 3xx response fails the page and its Location is logged")
+	fs.StringVar(&c.AcceptLanguage, "accept-language", c.AcceptLanguage, "Accept-Language header sent with every request (empty sends none)")
+	fs.Var(&listFlag{list: &c.RequestHeaders}, "header", "extra \"Key: Value\" header sent with every request (repeatable, overrides -accept-language for the same key)")
+	fs.Var(&listFlag{list: &c.Proxies}, "proxy", "send requests through this proxy, rotating between them (repeatable, http://, https:// or socks5://)")
+	fs.BoolVar(&c.ProxyTest, "proxy-test", c.ProxyTest, "check every -proxy against the board host, print their health and exit")
+	fs.BoolVar(&c.Render, "render", c.Render, "load listing pages in headless Chrome for boards rendered by JavaScript (needs -tags chromedp)")
+	fs.StringVar(&c.Sink, "sink", c.Sink, "also publish posts as each page is collected: kafka:host:port[,host:port]/topic (needs -tags kafka) or file:path (NDJSON, appended)")
+	fs.IntVar(&c.SinkFlushRecords, "sink-flush-records", c.SinkFlushRecords, "with -sink file:, flush to disk after this many posts (0 flushes after every page unless -sink-flush-interval is set)")
+	fs.Var(&c.SinkFlushInterval, "sink-flush-interval", "with -sink file:, flush to disk at least this often, e.g. 10s")
+	fs.StringVar(&c.Manifest, "manifest", c.Manifest, "write run information (version, timestamps, row count) as JSON to this file")
+	fs.StringVar(&c.SnapshotHTML, "snapshot-html", c.SnapshotHTML, "save the HTML of every listing page, gzipped and named by page number (12.html.gz), into this directory")
+	fs.StringVar(&c.FromSnapshots, "from-snapshots", c.FromSnapshots, "parse the pages saved by -snapshot-html in this directory instead of requesting the board (no network access)")
+
+	fs.StringVar(&c.Audit, "audit", c.Audit, "write one JSON line per HTTP request (url, attempt, status, bytes, duration, error) to this file")
+
+	fs.IntVar(&c.From, "from", c.From, "first page to scrape")
+	fs.IntVar(&c.To, "to", c.To, "last page to scrape (0 means the last discovered page)")
+
+	fs.IntVar(&c.PostsPerPage, "posts-per-page", c.PostsPerPage, "posts on one listing page, notices excluded, used to estimate the last page (0 counts them on the first page)")
+	fs.IntVar(&c.MaxPages, "max-pages", c.MaxPages, "never fetch more than N listing pages, whatever the discovered maximum (0 means no cap)")
+	fs.BoolVar(&c.FirstPageOnly, "first-page-only", c.FirstPageOnly, "fetch only page 1 without looking for the last page (the newest posts, fastest)")
+
+	fs.StringVar(&c.Mode, "mode", c.Mode, "listing to scrape: "+strings.Join(boardModeNames(), ", ")+"; each post's source field is set to it")
+	fs.StringVar(&c.Sort, "sort", c.Sort, "order of the results: "+strings.Join(sortOrders, ", ")+" (original keeps the board's page and row order, notices first)")
+	fs.StringVar(&c.ServerSort, "server-sort", c.ServerSort, "ask the board for a sorted listing: "+strings.Join(serverSortNames(), ", "))
+	fs.IntVar(&c.Limit, "limit", c.Limit, "keep only the first N posts of the listing, fetching just the pages needed (0 means no limit)")
+
+	fs.StringVar(&c.Sample, "sample", c.Sample, "scrape only a random fraction of pages, e.g. 10% or 0.1")
+	fs.IntVar(&c.SampleN, "sample-n", c.SampleN, "scrape only N randomly chosen pages")
+	fs.Int64Var(&c.Seed, "seed", c.Seed, "seed for everything random (-sample, proxy order); 0 picks a new seed each run and logs it")
+
+	fs.StringVar(&c.SeenDB, "seen-db", c.SeenDB, "file recording post numbers seen by earlier runs; unseen posts are marked in a New column")
+	fs.BoolVar(&c.SeenReset, "seen-reset", c.SeenReset, "with -seen-db, forget previously seen posts and start a new record")
+
+	fs.StringVar(&c.Watermark, "watermark", c.Watermark, "file holding the highest post number written so far; only newer posts are written and the mark is advanced")
+
+	fs.IntVar(&c.Workers, "workers", c.Workers, "number of pages fetched concurrently")
+	fs.IntVar(&c.MaxPerHost, "max-per-host", c.MaxPerHost, "at most this many requests in flight to one host, whatever -workers is (0 means no limit)")
+	fs.Float64Var(&c.RPS, "rps", c.RPS, "maximum requests per second (0 means unlimited)")
+	fs.Var(&c.SleepBetweenPages, "sleep-between-pages", "pause each worker this long after every page, e.g. 500ms, on top of -rps")
+
+	fs.BoolVar(&c.Adaptive, "adaptive", c.Adaptive, "adjust the request rate automatically from response times and errors")
+	fs.Float64Var(&c.MinRPS, "min-rps", c.MinRPS, "lower bound of the request rate in -adaptive mode")
+	fs.Float64Var(&c.MaxRPS, "max-rps", c.MaxRPS, "upper bound of the request rate in -adaptive mode")
+	fs.BoolVar(&c.ThrottleOnError, "throttle-on-error", c.ThrottleOnError, "slow the whole run down from -rps when many recent requests fail, and recover as they succeed")
+	fs.Float64Var(&c.ThrottleThreshold, "throttle-threshold", c.ThrottleThreshold, "with -throttle-on-error, slow down when more than this fraction of recent requests failed")
+	fs.IntVar(&c.ThrottleWindow, "throttle-window", c.ThrottleWindow, "with -throttle-on-error, number of recent requests the failure fraction is measured over")
+	fs.Float64Var(&c.ThrottleFactor, "throttle-factor", c.ThrottleFactor, "with -throttle-on-error, multiply the rate by this when slowing down")
+	fs.Float64Var(&c.ThrottleRecover, "throttle-recover", c.ThrottleRecover, "with -throttle-on-error, multiply the rate by this after each successful request, up to -rps")
+	fs.Float64Var(&c.ThrottleMinRPS, "throttle-min-rps", c.ThrottleMinRPS, "with -throttle-on-error, never slow down below this rate")
+
+	fs.BoolVar(&c.Normalize, "normalize", c.Normalize, "collapse runs of whitespace in titles and user names to a single space")
+	fs.BoolVar(&c.StripInvisible, "strip-invisible", c.StripInvisible, "with -normalize, also remove zero-width and bidirectional control characters")
+	fs.BoolVar(&c.NormalizeUnicode, "normalize-unicode", c.NormalizeUnicode, "convert titles and user names to Unicode NFC so decomposed Hangul compares equal")
+
+	fs.BoolVar(&c.DupTitles, "dup-titles", c.DupTitles, "flag posts sharing the same normalized title with a Dup Group column")
+
+	fs.StringVar(&c.PostProcess, "post-process", c.PostProcess, "external command that receives results as a JSON array on stdin and prints the processed array on stdout")
+
+	fs.BoolVar(&c.FailFast, "fail-fast", c.FailFast, "abort the run with a non-zero exit on the first page that fails")
+	fs.IntVar(&c.MaxFailuresAbort, "max-failures-abort", c.MaxFailuresAbort, "stop once more than this many pages have failed, write the partial results and exit with code 5 (0 means no limit)")
+	fs.BoolVar(&c.KeepGoing, "keep-going", c.KeepGoing, "skip failed pages, write partial results and report failures at the end (default)")
+
+	fs.Var(&c.Interval, "interval", "run again this long after each run ends, e.g. 10m, until interrupted (0 runs once)")
+	fs.StringVar(&c.IntervalJitter, "interval-jitter", c.IntervalJitter, "with -interval, wait a random time within this fraction of it, e.g. 20% waits between 0.8 and 1.2 times -interval (follows -seed)")
+	fs.BoolVar(&c.KeepWarm, "keep-warm", c.KeepWarm, "with -interval, keep connections to the board open between runs with a HEAD request every 30s")
+	fs.BoolVar(&c.ReportGaps, "report-gaps", c.ReportGaps, "print the post-number ranges missing between the lowest and highest collected post (mostly deleted posts)")
+	fs.StringVar(&c.ReportGapsFile, "report-gaps-file", c.ReportGapsFile, "with -report-gaps, write the missing ranges to this CSV file instead of stdout")
+	fs.BoolVar(&c.Stats, "stats", c.Stats, "print post count and view/comment/recommend totals of the collected posts")
+	fs.Var(&c.Timeout, "timeout", "stop scraping after this long, write what was collected and exit with code 6, e.g. 10m (0 means no limit)")
+	fs.Var(&c.TimeoutPerPage, "timeout-per-page", "give up on a page after this long, counting all of its retries, e.g. 60s (0 means no limit)")
+	fs.IntVar(&c.RetryFailures, "retry-failures", c.RetryFailures, "after the main pass, re-scrape only the failed pages up to N more rounds, waiting longer each round")
+	fs.BoolVar(&c.QuietOnEmpty, "quiet-on-empty", c.QuietOnEmpty, "exit with status 0 instead of 3 when no rows are written")
+
+	fs.StringVar(&c.Format, "format", c.Format, "output format: csv, json, ndjson or parquet (needs -tags parquet); a comma-separated list writes one file per format")
+	fs.StringVar(&c.Output, "o", c.Output, "output file (default pages.<format>)")
+	fs.StringVar(&c.OutputDir, "output-dir", c.OutputDir, "directory for the output files, named pages.<format>")
+	fs.StringVar(&c.PartitionBy, "partition-by", c.PartitionBy, "write the output into Hive-style directories per value of this column, e.g. date=2024-05-01/pages.csv (only date is supported)")
+	fs.Var(&listFlag{list: &c.ExtraFields}, "extra-field", "collect an extra column from each row: name=selector for the text or name=selector@attr for an attribute (repeatable, columns follow this order)")
+	fs.Var(&commaListFlag{list: &c.Fields}, "fields", "comma-separated columns for CSV and -pretty-table output, e.g. num,title,view")
+	fs.Var(&commaListFlag{list: &c.RequireFields}, "require-fields", "comma-separated fields that must not be empty, e.g. title,link,num; other rows are dropped (with -fail-fast the run stops)")
+	fs.BoolVar(&c.PrettyTable, "pretty-table", c.PrettyTable, "also print the results as an aligned table on stdout")
+	fs.IntVar(&c.TableWidth, "table-width", c.TableWidth, "fit -pretty-table and -compare-users tables to this many columns (0 uses the terminal width, or no limit when stdout is not a terminal)")
+	fs.IntVar(&c.Top, "top", c.Top, "with -pretty-table, print only the first N rows (0 prints all)")
+	fs.StringVar(&c.Color, "color", c.Color, "use color in terminal output: "+strings.Join(colorModes, ", ")+" (auto also honours NO_COLOR)")
+	fs.BoolVar(&c.NoColor, "no-color", c.NoColor, "same as -color never")
+	fs.BoolVar(&c.StripNewlines, "strip-newlines", c.StripNewlines, "replace newlines inside CSV values with spaces so every row is one line")
+	fs.BoolVar(&c.CRLF, "crlf", c.CRLF, "end CSV rows with \\r\\n instead of \\n")
+	fs.BoolVar(&c.ValidateOutput, "validate-output", c.ValidateOutput, "read each output file back after writing it and fail if rows or values do not round-trip")
+	fs.BoolVar(&c.CSVHeaderComment, "csv-header-comment", c.CSVHeaderComment, "write # comment lines with the board URL, scrape time, version and row count before the CSV header")
+	fs.BoolVar(&c.Compact, "compact", c.Compact, "write -format json output without indentation")
+	fs.BoolVar(&c.Thumbnails, "thumbnails", c.Thumbnails, "include the thumbnail image URL of each post in the output")
+	fs.BoolVar(&c.IncludeBadges, "include-badges", c.IncludeBadges, "include is_new, is_hot and has_image columns from the icons in each row")
+	fs.Var(&listFlag{list: &c.BadgeSelectors}, "badge-selector", "with -include-badges, find a badge with this selector instead of the default: name=selector, name is "+strings.Join(badgeNames(), ", ")+" (repeatable)")
+	fs.BoolVar(&c.Categories, "categories", c.Categories, "include the post category ([질문], [정보], ...) in the CSV output")
+	fs.BoolVar(&c.StripCategory, "strip-category", c.StripCategory, "remove a leading [category] prefix from titles")
+	fs.Var(&listFlag{list: &c.CategoryFilter}, "category", "keep only posts in this category (repeatable, case-insensitive)")
+	fs.Var(&listFlag{list: &c.Match}, "match", "keep only posts whose title contains this keyword (repeatable, case-insensitive)")
+	fs.Var(&listFlag{list: &c.Exclude}, "exclude", "drop posts whose title contains this keyword (repeatable, wins over -match)")
+	fs.BoolVar(&c.Regex, "regex", c.Regex, "treat -match and -exclude values as regular expressions")
+	fs.BoolVar(&c.Dates, "dates", c.Dates, "include the post date in the CSV output (relative times like 5분 전 are resolved against the scrape start)")
+	fs.BoolVar(&c.Counts, "counts", c.Counts, "include comment and recommend counts in the CSV output")
+	fs.BoolVar(&c.IncludeRawNum, "include-raw-num", c.IncludeRawNum, "include the original text of non-numeric number cells (e.g. 공지) in the CSV output")
+	fs.IntVar(&c.MinViews, "min-views", c.MinViews, "keep only posts with at least N views")
+	fs.IntVar(&c.MinComments, "min-comments", c.MinComments, "keep only posts with at least N comments")
+	fs.IntVar(&c.MinRecommend, "min-recommend", c.MinRecommend, "keep only posts with at least N recommendations")
+	fs.BoolVar(&c.Dedup, "dedup", c.Dedup, "keep a single row per post when it shows up on more than one page (-dedup=false to keep all)")
+	fs.StringVar(&c.DedupKey, "dedup-key", c.DedupKey, "output fields joined with + that identify a post for -dedup and merge, e.g. num, num+category or link; any -fields name or -extra-field name works (default: num, or link for posts without a number)")
+	fs.IntVar(&c.MinRows, "min-rows", c.MinRows, "flag listing pages (except the last) that parse fewer than N rows (0 disables the check)")
+	fs.StringVar(&c.MinRowsAction, "min-rows-action", c.MinRowsAction, "what to do with a page below -min-rows: "+strings.Join(minRowsActions, ", "))
+	fs.StringVar(&c.MissingViewAction, "missing-view-action", c.MissingViewAction, "what to do with a page whose rows have no view count cell (every view would be 0): "+strings.Join(missingViewActions, ", "))
+	fs.BoolVar(&c.KeepEmpty, "keep-empty", c.KeepEmpty, "keep rows without a title link (ads, layout variants) instead of skipping them with a warning")
+	fs.BoolVar(&c.IncludeDeleted, "include-deleted", c.IncludeDeleted, "keep soft-deleted posts and mark them in a Deleted column instead of skipping them")
+	fs.StringVar(&c.DownloadImages, "download-images", c.DownloadImages, "download each post's thumbnail into this directory, named by post number")
+	fs.BoolVar(&c.IncludeBodyLength, "include-body-length", c.IncludeBodyLength, "fetch every post page after scraping and record the body's character count in a body_len column (one extra request per post)")
+
+	fs.BoolVar(&c.BOM, "bom", c.BOM, "prepend a UTF-8 BOM to CSV output so Excel renders Hangul correctly")
+	fs.StringVar(&c.Encoding, "encoding", c.Encoding, "output encoding: utf-8 or euc-kr")
+
+	fs.StringVar(&c.Lang, "lang", c.Lang, "language of the CSV header row: en or ko")
+	fs.Var(&c.Headers, "headers", "override CSV headers, e.g. title=제목,view=조회수")
+
+	// -config는 flag 파싱 전에 configPathFromArgs로 먼저 읽습니다. 여기서는 -help에 보이도록 등록만 합니다.
+	fs.String("config", "", "JSON config file; keys are option names, command-line options take precedence")
+}
+
+// 환경 변수 이름의 접두어. -rps는 SCRAPER_RPS, -min-rows는 SCRAPER_MIN_ROWS로 줍니다.
+const envPrefix = "SCRAPER_"
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// 옵션마다 envName의 환경 변수가 있으면 그 값으로 c를 덮어씁니다.
+// 설정 파일을 읽은 뒤, 명령행 옵션을 등록하기 전에 호출해서 "명령행 > 환경 변수 > 설정 파일 > 기본값" 순서가 되게 합니다.
+// 여러 번 줄 수 있는 옵션(-match 등)은 환경 변수로는 값 하나만 줄 수 있습니다.
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	c.registerFlags(fs)
+
+	problems := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		name := envName(f.Name)
+		if value, exists := lookup(name); exists {
+			if err := fs.Set(f.Name, value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%q: %v", name, value, err))
+			}
+		}
+	})
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid environment variables:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// 명령행 인자에서 -config 값을 미리 찾습니다. 없으면 SCRAPER_CONFIG를 사용합니다.
+// 설정 파일의 값이 flag의 기본값이 되어야 하므로 flag.Parse보다 먼저 읽어야 합니다.
+func configPathFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		if value, found := strings.CutPrefix(name, "config="); found {
+			return value
+		}
+		if name == "config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv(envName("config"))
+}
+
+// JSON 설정 파일을 읽어서 c를 덮어씁니다. 키는 명령행 옵션 이름과 같습니다. ({"workers": 4, "lang": "ko"})
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	dec := json.NewDecoder(file)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// 옵션 사이의 관계까지 포함해서 설정 전체를 검사하고, 발견한 문제를 모두 모아서 하나의 에러로 리턴합니다.
+// 실행 도중에 잘못된 옵션 때문에 멈추는 일이 없도록 main에서 수집을 시작하기 전에 호출합니다.
+func (c Config) Validate() error {
+	problems := []string{}
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.BaseURL == "-" {
+		if c.SeenDB != "" || c.Watermark != "" {
+			addProblem("-url - cannot be used with -seen-db or -watermark, which track post numbers of a single board")
+		}
+		if c.CheckOnly || c.PrintURLTemplate || c.Explain != 0 {
+			addProblem("-url - cannot be used with -check, -print-url-template or -explain")
+		}
+	} else if err := checkBaseURL(c.BaseURL); err != nil {
+		addProblem("%v", err)
+	}
+	if c.BaseURL == "-" && c.ReportGaps {
+		addProblem("-url - cannot be used with -report-gaps; post numbers of different boards do not form one range")
+	}
+	if c.PerBoard && c.BaseURL != "-" {
+		addProblem("-per-board requires -url -")
+	}
+	if c.FromSnapshots != "" {
+		// 요청이 필요한 옵션은 같이 쓸 수 없습니다.
+		network := []string{}
+		for _, opt := range []struct {
+			name string
+			set  bool
+		}{
+			{"-url -", c.BaseURL == "-"},
+			{"-snapshot-html", c.SnapshotHTML != ""},
+			{"-json-endpoint", c.JSONEndpoint != ""},
+			{"-render", c.Render},
+			{"-proxy", len(c.Proxies) > 0},
+			{"-download-images", c.DownloadImages != ""},
+			{"-include-body-length", c.IncludeBodyLength},
+			{"-check", c.CheckOnly},
+			{"-explain", c.Explain != 0},
+		} {
+			if opt.set {
+				network = append(network, opt.name)
+			}
+		}
+		if len(network) > 0 {
+			addProblem("-from-snapshots makes no requests and cannot be used with %s", strings.Join(network, ", "))
+		}
+	}
+	if c.PageTemplate != "" {
+		if !strings.Contains(c.PageTemplate, "{n}") {
+			addProblem("-page-template %q has no {n} placeholder for the page number", c.PageTemplate)
+		} else if u, err := url.Parse(expandPageTemplate(c.PageTemplate, c.BaseURL, nil, "1")); c.BaseURL != "-" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			addProblem("-page-template %q does not produce an absolute http(s) URL", c.PageTemplate)
+		}
+	}
+	if c.JSONEndpoint != "" {
+		if !strings.Contains(c.JSONEndpoint, "{n}") {
+			addProblem("-json-endpoint %q has no {n} placeholder for the page number", c.JSONEndpoint)
+		} else if u, err := url.Parse(expandPageTemplate(c.JSONEndpoint, c.BaseURL, nil, "1")); c.BaseURL != "-" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			addProblem("-json-endpoint %q does not produce an absolute http(s) URL", c.JSONEndpoint)
+		}
+		if c.Render {
+			addProblem("-json-endpoint cannot be used with -render")
+		}
+		if len(c.ExtraFields) > 0 {
+			addProblem("-extra-field reads HTML rows and cannot be used with -json-endpoint")
+		}
+		if c.SnapshotHTML != "" {
+			addProblem("-snapshot-html saves HTML listing pages and cannot be used with -json-endpoint")
+		}
+	}
+	if c.JSONListKey != "" && c.JSONEndpoint == "" {
+		addProblem("-json-list-key requires -json-endpoint")
+	}
+
+	if c.MaxRedirects < 0 {
+		addProblem("-max-redirects must not be negative (got %d)", c.MaxRedirects)
+	}
+
+	for _, selector := range c.BlockSelectors {
+		if err := checkSelector(selector); err != nil {
+			addProblem("-block-selector %q: %v", selector, err)
+		}
+	}
+
+	if c.Sink != "" {
+		if err := checkSink(c.Sink); err != nil {
+			addProblem("%v", err)
+		}
+	}
+	if c.SinkFlushRecords < 0 {
+		addProblem("-sink-flush-records must not be negative (got %d)", c.SinkFlushRecords)
+	}
+	if c.SinkFlushInterval < 0 {
+		addProblem("-sink-flush-interval must not be negative (got %v)", time.Duration(c.SinkFlushInterval))
+	}
+	if (c.SinkFlushRecords > 0 || c.SinkFlushInterval > 0) && !strings.HasPrefix(c.Sink, "file:") {
+		addProblem("-sink-flush-records and -sink-flush-interval require -sink file:<path>")
+	}
+
+	for _, header := range c.RequestHeaders {
+		if _, _, err := parseHeader(header); err != nil {
+			addProblem("-header: %v", err)
+		}
+	}
+
+	for _, proxy := range c.Proxies {
+		if _, err := parseProxy(proxy); err != nil {
+			addProblem("-proxy %q: %v", proxy, err)
+		}
+	}
+	if c.ProxyTest && len(c.Proxies) == 0 {
+		addProblem("-proxy-test requires at least one -proxy")
+	}
+
+	if c.Render && !renderSupported {
+		addProblem("-render requires a binary built with -tags chromedp")
+	}
+
+	if c.From < 1 {
+		addProblem("-from must be at least 1 (got %d)", c.From)
+	}
+	if c.To < 0 {
+		addProblem("-to must not be negative (got %d)", c.To)
+	}
+	if c.To != 0 && c.From > c.To {
+		addProblem("-from (%d) must not be greater than -to (%d)", c.From, c.To)
+	}
+
+	if c.PostsPerPage < 0 {
+		addProblem("-posts-per-page must not be negative (got %d)", c.PostsPerPage)
+	}
+	if c.MaxPages < 0 {
+		addProblem("-max-pages must not be negative (got %d)", c.MaxPages)
+	}
+	if c.FirstPageOnly {
+		// 수집할 page가 1 page로 정해져 있으므로 page를 고르는 옵션과는 같이 쓸 수 없습니다.
+		if c.From != 1 || c.To != 0 || c.MaxPages != 0 {
+			addProblem("-first-page-only cannot be used with -from, -to or -max-pages")
+		}
+		if c.Sample != "" || c.SampleN != 0 {
+			addProblem("-first-page-only cannot be used with -sample or -sample-n")
+		}
+		if c.FromSnapshots != "" {
+			addProblem("-first-page-only cannot be used with -from-snapshots")
+		}
+	}
+
+	if _, exists := boardModeParams[c.Mode]; !exists {
+		addProblem("-mode %q is not supported (expected %s)", c.Mode, strings.Join(boardModeNames(), ", "))
+	}
+	validSort := false
+	for _, order := range sortOrders {
+		validSort = validSort || order == c.Sort
+	}
+	if !validSort {
+		addProblem("-sort %q is not supported (expected %s)", c.Sort, strings.Join(sortOrders, ", "))
+	}
+	if _, exists := serverSortParams[c.ServerSort]; c.ServerSort != "" && !exists {
+		addProblem("-server-sort %q is not supported (expected %s)", c.ServerSort, strings.Join(serverSortNames(), ", "))
+	}
+	if c.Limit < 0 {
+		addProblem("-limit must not be negative (got %d)", c.Limit)
+	}
+	if c.Limit > 0 && (c.Sample != "" || c.SampleN != 0) {
+		addProblem("-limit cannot be used with -sample or -sample-n")
+	}
+
+	if _, err := parseSampleRate(c.Sample); err != nil {
+		addProblem("-sample: %v", err)
+	}
+	if c.SampleN < 0 {
+		addProblem("-sample-n must not be negative (got %d)", c.SampleN)
+	}
+	if c.Sample != "" && c.SampleN != 0 {
+		addProblem("-sample and -sample-n cannot be used together")
+	}
+
+	if c.SeenReset && c.SeenDB == "" {
+		addProblem("-seen-reset requires -seen-db")
+	}
+
+	if c.Workers < 1 {
+		addProblem("-workers must be at least 1 (got %d)", c.Workers)
+	}
+	if c.MaxPerHost < 0 {
+		addProblem("-max-per-host must not be negative (got %d)", c.MaxPerHost)
+	}
+	if c.RPS < 0 {
+		addProblem("-rps must not be negative (got %v)", c.RPS)
+	}
+	if c.SleepBetweenPages < 0 {
+		addProblem("-sleep-between-pages must not be negative (got %v)", time.Duration(c.SleepBetweenPages))
+	}
+	if c.Adaptive {
+		if c.MinRPS <= 0 {
+			addProblem("-min-rps must be greater than 0 (got %v)", c.MinRPS)
+		}
+		if c.MaxRPS < c.MinRPS {
+			addProblem("-max-rps (%v) must not be less than -min-rps (%v)", c.MaxRPS, c.MinRPS)
+		}
+	}
+	if c.ThrottleOnError {
+		if c.RPS <= 0 {
+			addProblem("-throttle-on-error needs a request rate to slow down from; set -rps")
+		}
+		if c.Adaptive {
+			addProblem("-throttle-on-error cannot be combined with -adaptive, which already slows down on errors")
+		}
+		if c.ThrottleThreshold <= 0 || c.ThrottleThreshold >= 1 {
+			addProblem("-throttle-threshold must be between 0 and 1 (got %v)", c.ThrottleThreshold)
+		}
+		if c.ThrottleWindow < 2 {
+			addProblem("-throttle-window must be at least 2 (got %d)", c.ThrottleWindow)
+		}
+		if c.ThrottleFactor <= 0 || c.ThrottleFactor >= 1 {
+			addProblem("-throttle-factor must be between 0 and 1 (got %v)", c.ThrottleFactor)
+		}
+		if c.ThrottleRecover <= 1 {
+			addProblem("-throttle-recover must be greater than 1 (got %v)", c.ThrottleRecover)
+		}
+		if c.ThrottleMinRPS <= 0 {
+			addProblem("-throttle-min-rps must be greater than 0 (got %v)", c.ThrottleMinRPS)
+		}
+	}
+
+	for _, category := range c.CategoryFilter {
+		if strings.TrimSpace(category) == "" {
+			addProblem("-category must not be empty")
+		}
+	}
+
+	if _, err := keywordFilter(c.Match, c.Exclude, c.Regex); err != nil {
+		addProblem("-match/-exclude: %v", err)
+	}
+
+	if c.MinViews < 0 || c.MinComments < 0 || c.MinRecommend < 0 {
+		addProblem("-min-views, -min-comments and -min-recommend must not be negative")
+	}
+
+	if c.MinRows < 0 {
+		addProblem("-min-rows must not be negative (got %d)", c.MinRows)
+	}
+	validAction := false
+	for _, action := range minRowsActions {
+		validAction = validAction || action == c.MinRowsAction
+	}
+	if !validAction {
+		addProblem("-min-rows-action %q is not supported (expected %s)", c.MinRowsAction, strings.Join(minRowsActions, ", "))
+	}
+	validAction = false
+	for _, action := range missingViewActions {
+		validAction = validAction || action == c.MissingViewAction
+	}
+	if !validAction {
+		addProblem("-missing-view-action %q is not supported (expected %s)", c.MissingViewAction, strings.Join(missingViewActions, ", "))
+	}
+
+	if c.FailFast && c.KeepGoing {
+		addProblem("-fail-fast and -keep-going cannot be used together")
+	}
+
+	if c.RetryFailures < 0 {
+		addProblem("-retry-failures must not be negative (got %d)", c.RetryFailures)
+	}
+	if c.MaxFailuresAbort < 0 {
+		addProblem("-max-failures-abort must not be negative (got %d)", c.MaxFailuresAbort)
+	}
+	if c.MaxFailuresAbort > 0 && c.FailFast {
+		addProblem("-max-failures-abort cannot be used with -fail-fast")
+	}
+	if c.Interval < 0 {
+		addProblem("-interval must not be negative (got %v)", time.Duration(c.Interval))
+	}
+	if c.KeepWarm && c.Interval == 0 {
+		addProblem("-keep-warm requires -interval")
+	}
+	if c.HealthAddr != "" && c.Interval == 0 {
+		addProblem("-health-addr requires -interval")
+	}
+	if _, err := parseIntervalJitter(c.IntervalJitter); err != nil {
+		addProblem("-interval-jitter: %v", err)
+	} else if c.IntervalJitter != "" && c.Interval == 0 {
+		addProblem("-interval-jitter requires -interval")
+	}
+	if c.ReportGapsFile != "" && !c.ReportGaps {
+		addProblem("-report-gaps-file requires -report-gaps")
+	}
+	if c.TimeoutPerPage < 0 {
+		addProblem("-timeout-per-page must not be negative (got %v)", time.Duration(c.TimeoutPerPage))
+	}
+	if c.Timeout < 0 {
+		addProblem("-timeout must not be negative (got %v)", time.Duration(c.Timeout))
+	}
+	if c.RetryFailures > 0 && c.FailFast {
+		addProblem("-retry-failures cannot be used with -fail-fast")
+	}
+
+	if c.StripInvisible && !c.Normalize {
+		addProblem("-strip-invisible requires -normalize")
+	}
+
+	formats := map[string]bool{}
+	for _, format := range c.formats() {
+		if err := checkFormat(format); err != nil {
+			addProblem("%v", err)
+		}
+		if isColumnarFormat(format) && c.ValidateOutput {
+			addProblem("-validate-output cannot check -format %s", format)
+		}
+		if formats[format] {
+			addProblem("-format lists %q more than once", format)
+		}
+		formats[format] = true
+	}
+	if len(formats) > 1 && c.Output != "" {
+		addProblem("-o cannot be used with more than one -format; use -output-dir")
+	}
+	if c.Output != "" && c.OutputDir != "" {
+		addProblem("-o and -output-dir cannot be used together")
+	}
+	if err := checkEncoding(c.Encoding, c.BOM); err != nil {
+		addProblem("%v", err)
+	}
+	if _, exists := headerLanguages[c.Lang]; !exists {
+		addProblem("-lang %q is not supported (expected en or ko)", c.Lang)
+	}
+	if len(c.CompareUsers) > 0 && len(c.CompareUsers) != 2 {
+		addProblem("-compare-users needs two files, previous,current (got %d)", len(c.CompareUsers))
+	}
+	if c.ConfirmPages < 0 {
+		addProblem("-confirm-pages must not be negative (got %d)", c.ConfirmPages)
+	}
+	if c.Explain < 0 {
+		addProblem("-explain must be a page number (got %d)", c.Explain)
+	}
+	if c.PartitionBy != "" && c.PartitionBy != "date" {
+		addProblem("-partition-by %q is not supported (expected date)", c.PartitionBy)
+	}
+	for _, spec := range c.BadgeSelectors {
+		if _, _, err := parseBadgeSelector(spec); err != nil {
+			addProblem("-badge-selector: %v", err)
+		}
+	}
+	if len(c.BadgeSelectors) > 0 && !c.IncludeBadges {
+		addProblem("-badge-selector requires -include-badges")
+	}
+	extraNames := map[string]bool{}
+	for _, spec := range c.ExtraFields {
+		f, err := parseExtraField(spec)
+		switch {
+		case err != nil:
+			addProblem("-extra-field: %v", err)
+		case extraNames[f.name]:
+			addProblem("-extra-field: %q is given more than once", f.name)
+		default:
+			if _, exists := findOutputField(f.name); exists {
+				addProblem("-extra-field: %q is already a built-in field", f.name)
+			}
+		}
+		extraNames[f.name] = true
+	}
+	for _, name := range c.Fields {
+		if _, exists := c.findField(name); !exists {
+			addProblem("-fields: unknown field %q", name)
+		}
+	}
+	for _, name := range c.RequireFields {
+		if _, exists := c.findField(name); !exists {
+			addProblem("-require-fields: unknown field %q", name)
+		}
+	}
+	for _, name := range parseDedupKey(c.DedupKey) {
+		if name == "" {
+			addProblem("-dedup-key %q has an empty field (expected fields joined with +, e.g. num+category)", c.DedupKey)
+		} else if _, exists := c.findField(name); !exists {
+			addProblem("-dedup-key: unknown field %q (expected output fields joined with +: %s, or an -extra-field name)", name, strings.Join(outputFieldNames(), ", "))
+		}
+	}
+	if c.DedupKey != "" && !c.Dedup {
+		addProblem("-dedup-key requires -dedup")
+	}
+	if c.Top < 0 {
+		addProblem("-top must not be negative (got %d)", c.Top)
+	}
+	if c.TableWidth < 0 {
+		addProblem("-table-width must not be negative (got %d)", c.TableWidth)
+	}
+	if c.Top > 0 && !c.PrettyTable {
+		addProblem("-top requires -pretty-table")
+	}
+	validColor := false
+	for _, mode := range colorModes {
+		validColor = validColor || mode == c.Color
+	}
+	if !validColor {
+		addProblem("-color %q is not supported (expected %s)", c.Color, strings.Join(colorModes, ", "))
+	}
+	if c.NoColor && c.Color == "always" {
+		addProblem("-no-color and -color always cannot be used together")
+	}
+
+	for name := range c.Headers {
+		if _, exists := c.findField(name); !exists {
+			addProblem("-headers: unknown field %q", name)
+		}
+	}
+	if c.Compact && !formats["json"] {
+		addProblem("-compact can only be used with -format json")
+	}
+	if c.BOM && !formats["csv"] {
+		addProblem("-bom can only be used with -format csv")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+}
+
+// -proxy 목록을 URL로 바꿉니다. Validate를 통과한 설정이라고 가정합니다.
+func (c Config) proxyURLs() []*url.URL {
+	proxies := []*url.URL{}
+	for _, raw := range c.Proxies {
+		proxy, _ := parseProxy(raw)
+		proxies = append(proxies, proxy)
+	}
+	return proxies
+}
+
+// -format에 준 형식 목록 ("csv,json" -> csv, json)
+func (c Config) formats() []string {
+	formats := []string{}
+	for _, format := range strings.Split(c.Format, ",") {
+		formats = append(formats, strings.TrimSpace(format))
+	}
+	return formats
+}
+
+// format 형식의 결과를 쓸 파일 경로
+func (c Config) outputPath(format string) string {
+	if c.Output != "" {
+		return c.Output
+	}
+	return filepath.Join(c.OutputDir, "pages."+format)
+}
+
+// 설정에 맞는 Scraper 옵션 목록을 만듭니다. Validate를 통과한 설정이라고 가정합니다.
+func (c Config) scraperOptions() []Option {
+	opts := []Option{
+		WithBaseURL(c.BaseURL),
+		WithPageTemplate(c.PageTemplate),
+		WithSnapshotDir(c.SnapshotHTML),
+		WithSnapshotSource(c.FromSnapshots),
+		WithJSONEndpoint(c.JSONEndpoint, c.JSONListKey),
+		WithSeed(c.Seed),
+		WithPageRange(c.From, c.To),
+		WithMaxPages(c.MaxPages),
+		WithFirstPageOnly(c.FirstPageOnly),
+		WithPostsPerPage(c.PostsPerPage),
+		WithRequiredFields(c.requiredFields()),
+		WithMode(c.Mode),
+		WithExtraFields(c.extraFields()),
+		WithBadges(c.badgeSelectors()),
+		WithServerSort(c.ServerSort),
+		WithOriginalOrder(c.Sort == "original"),
+		WithLimit(c.Limit),
+		WithMaxFailures(c.MaxFailuresAbort),
+		WithWorkers(c.Workers),
+		WithMaxPerHost(c.MaxPerHost),
+		WithRateLimit(c.RPS),
+		WithPageSleep(time.Duration(c.SleepBetweenPages)),
+		WithDeleted(c.IncludeDeleted),
+		WithUnicodeNormalization(c.NormalizeUnicode),
+		WithKeepEmpty(c.KeepEmpty),
+		WithMinRows(c.MinRows, c.MinRowsAction),
+		WithMissingViewAction(c.MissingViewAction),
+		WithDedup(c.Dedup),
+		WithDedupKey(c.dedupFields()),
+		WithFailFast(c.FailFast),
+		WithVerbose(c.Verbose),
+		WithAcceptLanguage(c.AcceptLanguage),
+		WithHeaders(c.requestHeaders()),
+		WithRedirects(c.MaxRedirects, !c.NoRedirects),
+		WithBlockRules(c.BlockSelectors, c.BlockTitles, c.BlockURLs),
+		WithRetryFailures(c.RetryFailures),
+		WithPageTimeout(time.Duration(c.TimeoutPerPage)),
+	}
+	if c.KeepWarm {
+		opts = append(opts, WithKeepWarm(time.Duration(c.Interval)))
+	}
+	if c.Render {
+		opts = append(opts, WithRender())
+	}
+	if c.Adaptive {
+		opts = append(opts, WithAdaptiveRate(c.MinRPS, c.MaxRPS))
+	}
+	if c.ThrottleOnError {
+		opts = append(opts, WithThrottleOnError(throttleSettings{
+			threshold: c.ThrottleThreshold,
+			window:    c.ThrottleWindow,
+			factor:    c.ThrottleFactor,
+			recovery:  c.ThrottleRecover,
+			minRate:   c.ThrottleMinRPS,
+		}))
+	}
+
+	jitter, _ := parseIntervalJitter(c.IntervalJitter)
+	opts = append(opts, WithIntervalJitter(jitter))
+	sampleRate, _ := parseSampleRate(c.Sample)
+	if sampleRate > 0 || c.SampleN > 0 {
+		opts = append(opts, WithSample(sampleRate, c.SampleN))
+	}
+
+	if c.Normalize {
+		opts = append(opts, WithPostProcessor(normalizeProcessor(c.StripInvisible)))
+	}
+	if c.StripCategory {
+		opts = append(opts, WithPostProcessor(stripCategoryProcessor))
+	}
+	if len(c.CategoryFilter) > 0 {
+		opts = append(opts, WithPostProcessor(categoryFilter(c.CategoryFilter)))
+	}
+	if len(c.Match) > 0 || len(c.Exclude) > 0 {
+		filter, _ := keywordFilter(c.Match, c.Exclude, c.Regex)
+		opts = append(opts, WithPostProcessor(filter))
+	}
+	if c.MinViews > 0 || c.MinComments > 0 || c.MinRecommend > 0 {
+		opts = append(opts, WithPostProcessor(countFilter(c.MinViews, c.MinComments, c.MinRecommend)))
+	}
+	if c.DupTitles {
+		opts = append(opts, WithPostProcessor(duplicateTitleProcessor))
+	}
+	if c.PostProcess != "" {
+		opts = append(opts, WithPostProcessor(commandPostProcessor(c.PostProcess)))
+	}
+	if c.DownloadImages != "" {
+		opts = append(opts, WithImageDownload(c.DownloadImages))
+	}
+	if c.IncludeBodyLength {
+		opts = append(opts, WithBodyLength(true))
+	}
+	return opts
+}
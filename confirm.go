@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// 수집할 page가 이보다 많으면 시작하기 전에 물어봅니다. (-confirm-pages)
+const defaultConfirmPages = 1000
+
+var errNotConfirmed = errors.New("large scrape was not confirmed, nothing was requested (pass -yes to skip the prompt)")
+
+// 수집할 page 수가 threshold를 넘으면 요청을 시작하기 전에 confirm을 부르고, false를 리턴하면 errNotConfirmed로 중단합니다.
+func WithConfirm(threshold int, confirm func(pages int) bool) Option {
+	return func(s *Scraper) {
+		s.confirmThreshold = threshold
+		s.confirm = confirm
+	}
+}
+
+// 예상 요청 수와 시간을 출력하고 진행할지 묻습니다. y나 yes를 입력해야 진행합니다.
+func promptLargeScrape(in io.Reader, out io.Writer, pages int, rps float64) bool {
+	fmt.Fprintf(out, "this run will request about %d listing pages", pages)
+	if rps > 0 {
+		estimate := time.Duration(float64(pages) / rps * float64(time.Second))
+		fmt.Fprintf(out, ", which takes about %v at -rps %v", estimate.Round(time.Second), rps)
+	} else {
+		fmt.Fprint(out, " with no -rps limit on the board")
+	}
+	fmt.Fprint(out, ".\ncontinue? [y/N] ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
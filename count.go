@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 축약해서 나오는 숫자의 단위. ("1.2k", "3.4만")
+var countUnits = map[string]float64{
+	"k": 1e3,
+	"K": 1e3,
+	"천": 1e3,
+	"만": 1e4,
+	"억": 1e8,
+}
+
+// 목록에 나오는 조회수, 댓글 수, 추천 수를 읽습니다. 게시글 번호는 parsePostNumber로 읽습니다.
+// 공백과 천 단위 쉼표, "[12]"나 "(12)"처럼 감싼 괄호는 지우고, "1.2k", "3.4만" 같은 축약은 단위를 곱해서 반올림합니다.
+// 빈 값과 "-"는 값이 없다는 뜻이라 에러 없이 0입니다. 음수와 숫자가 아닌 값은 에러입니다.
+func parseCount(s string) (int, error) {
+	text := normalizeNumber(s)
+	for len(text) >= 2 && (text[0] == '[' && text[len(text)-1] == ']' || text[0] == '(' && text[len(text)-1] == ')') {
+		text = text[1 : len(text)-1]
+	}
+	if text == "" || text == "-" {
+		return 0, nil
+	}
+
+	for unit, multiplier := range countUnits {
+		number, found := strings.CutSuffix(text, unit)
+		if !found || number == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(number, 64)
+		if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) || strings.ContainsAny(number, "eExXpP+-") {
+			return 0, fmt.Errorf("%q is not a count", s)
+		}
+		n := math.Round(f * multiplier)
+		if n >= math.MaxInt64 {
+			return 0, fmt.Errorf("%q is out of range", s)
+		}
+		return int(n), nil
+	}
+
+	if text[0] == '+' || text[0] == '-' {
+		return 0, fmt.Errorf("%q is not a count", s)
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return 0, fmt.Errorf("%q is out of range", s)
+		}
+		return 0, fmt.Errorf("%q is not a count", s)
+	}
+	return n, nil
+}
+
+// 숫자 사이의 공백과 천 단위 쉼표를 지웁니다.
+func normalizeNumber(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), ""), ",", "")
+}
+
+// 목록에 나오는 게시글 번호를 읽습니다. 공백과 천 단위 쉼표는 parseCount처럼 지우지만,
+// 게시글 번호는 축약해서 나오지 않으므로 "1.2k" 같은 값은 "공지"처럼 숫자가 아닌 값으로 에러입니다.
+func parsePostNumber(s string) (int, error) {
+	text := normalizeNumber(s)
+	if text == "" || text[0] == '+' || text[0] == '-' {
+		return 0, fmt.Errorf("%q is not a post number", s)
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a post number", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// -keep-warm에서 실행 사이에 연결을 유지하려고 보내는 요청의 간격.
+// 게시판 서버가 idle 연결을 닫는 시간(보통 1분 안팎)보다 짧아야 합니다.
+const keepWarmPeriod = 30 * time.Second
+
+// -interval 실행 사이에 board host로의 keep-alive 연결을 닫지 않고 유지합니다.
+// transport의 IdleConnTimeout을 interval보다 길게 늘리고, 기다리는 동안 keepWarmPeriod마다 HEAD 요청을 보냅니다.
+func WithKeepWarm(interval time.Duration) Option {
+	return func(s *Scraper) {
+		s.keepWarm = interval
+	}
+}
+
+// -interval 실행 사이에 기다리는 시간을 interval의 ±fraction 안에서 실행마다 무작위로 정합니다. (0이면 항상 interval)
+// 여러 instance가 같은 시각에 몰리거나 요청 간격이 정확히 반복되지 않게 합니다. 난수는 -seed를 따릅니다.
+func WithIntervalJitter(fraction float64) Option {
+	return func(s *Scraper) {
+		s.intervalJitter = fraction
+	}
+}
+
+// -interval-jitter 값("20%" 또는 "0.2")을 비율로 바꿉니다. 빈 값은 0입니다.
+func parseIntervalJitter(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	percent := strings.HasSuffix(s, "%")
+	fraction, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval jitter %q: %w", s, err)
+	}
+	if percent {
+		fraction /= 100
+	}
+	if fraction < 0 || fraction >= 1 {
+		return 0, fmt.Errorf("invalid interval jitter %q: must be at least 0%% and below 100%%", s)
+	}
+	return fraction, nil
+}
+
+// 다음 실행까지 기다릴 시간. d를 WithIntervalJitter의 비율만큼 무작위로 늘이거나 줄입니다.
+func (s *Scraper) nextInterval(d time.Duration) time.Duration {
+	if s.intervalJitter <= 0 {
+		return d
+	}
+	offset := (s.rng.Float64()*2 - 1) * s.intervalJitter
+	return time.Duration(float64(d) * (1 + offset))
+}
+
+// WithKeepWarm을 준 경우 http.Client의 transport를 복사해서 IdleConnTimeout을 늘립니다.
+// http.DefaultTransport는 다른 코드와 같이 쓰므로 직접 바꾸지 않습니다. *http.Transport가 아니면 그대로 둡니다.
+func (s *Scraper) applyKeepWarm() {
+	if s.keepWarm <= 0 {
+		return
+	}
+	transport := s.client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		return
+	}
+	t = t.Clone()
+	if idle := s.keepWarm + keepWarmPeriod; t.IdleConnTimeout < idle {
+		t.IdleConnTimeout = idle
+	}
+	client := *s.client
+	client.Transport = t
+	s.client = &client
+}
+
+// -interval 실행 사이에 d만큼 기다립니다. keep-warm이면 기다리는 동안 boards에 HEAD 요청을 보내서 연결을 유지합니다.
+// ctx가 취소되면 false를 리턴합니다.
+func (s *Scraper) idle(ctx context.Context, d time.Duration, boards []string) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var ping <-chan time.Time
+	if s.keepWarm > 0 {
+		ticker := time.NewTicker(keepWarmPeriod)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case <-ctx.Done():
+			return false
+		case <-ping:
+			for _, board := range boards {
+				s.ping(ctx, board)
+			}
+		}
+	}
+}
+
+// 연결을 유지하기 위한 HEAD 요청. 실패해도 다음 실행에서 새로 연결하면 되므로 -v일 때만 기록합니다.
+func (s *Scraper) ping(ctx context.Context, url string) {
+	res, err := s.request(ctx, http.MethodHead, url)
+	if err != nil {
+		if s.verbose && ctx.Err() == nil {
+			log.Printf("keep-warm: %s: %v\n", url, err)
+		}
+		return
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+}
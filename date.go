@@ -0,0 +1,113 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 게시판에 나오는 시간은 한국 시간입니다. tzdata가 없는 환경에서도 동작하도록 고정 offset을 사용합니다.
+var boardLocation = time.FixedZone("KST", 9*60*60)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = time.RFC3339
+)
+
+var (
+	relativeTimePattern = regexp.MustCompile(`^(\d+)\s*(초|분|시간|일)\s*전$`)
+	clockPattern        = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+)
+
+// 절대 날짜 형식들. 연도가 없는 형식(01-02)은 parseDate에서 따로 처리합니다.
+var absoluteDateLayouts = []struct {
+	layout   string
+	withTime bool
+}{
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02 15:04", true},
+	{"2006.01.02 15:04", true},
+	{"2006-01-02", false},
+	{"2006.01.02", false},
+	{"06.01.02", false},
+	{"06-01-02", false},
+}
+
+// 목록의 등록일 칸을 now 기준의 절대 시간으로 바꿔서 문자열로 리턴합니다.
+// 시간까지 알 수 있으면 RFC3339("2024-05-01T10:30:00+09:00"), 날짜만 알 수 있으면 "2024-05-01" 형태입니다.
+// 다음 형식을 알아봅니다.
+//   - 오늘 글: "10:30"
+//   - 올해 글: "05-01" (now보다 뒤의 날짜라면 작년 글)
+//   - 절대 날짜: "2024-05-01", "2024.05.01", "24.05.01", "2024-05-01 10:30"
+//   - 상대 시간: "방금", "5초 전", "5분 전", "1시간 전", "3일 전", "어제", "그제", "어제 10:30"
+//
+// 알아볼 수 없는 형식이면 ok가 false입니다.
+func parseDate(raw string, now time.Time) (string, bool) {
+	text := strings.Join(strings.Fields(raw), " ")
+	now = now.In(boardLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, boardLocation)
+
+	switch text {
+	case "방금", "방금 전":
+		return now.Truncate(time.Minute).Format(dateTimeLayout), true
+	case "오늘":
+		return today.Format(dateLayout), true
+	case "어제":
+		return today.AddDate(0, 0, -1).Format(dateLayout), true
+	case "그제", "그저께":
+		return today.AddDate(0, 0, -2).Format(dateLayout), true
+	}
+
+	if m := relativeTimePattern.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "초":
+			return now.Add(-time.Duration(n) * time.Second).Truncate(time.Second).Format(dateTimeLayout), true
+		case "분":
+			return now.Add(-time.Duration(n) * time.Minute).Truncate(time.Minute).Format(dateTimeLayout), true
+		case "시간":
+			return now.Add(-time.Duration(n) * time.Hour).Truncate(time.Minute).Format(dateTimeLayout), true
+		case "일":
+			return today.AddDate(0, 0, -n).Format(dateLayout), true
+		}
+	}
+
+	// "어제 10:30"
+	day := today
+	if rest, found := strings.CutPrefix(text, "어제 "); found {
+		day = today.AddDate(0, 0, -1)
+		text = rest
+	}
+	if m := clockPattern.FindStringSubmatch(text); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		if hour < 24 && minute < 60 {
+			return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute).Format(dateTimeLayout), true
+		}
+		return "", false
+	}
+	if day != today {
+		return "", false
+	}
+
+	// 연도가 없는 "05-01"은 올해 날짜이고, 아직 오지 않은 날짜라면 작년 날짜입니다.
+	if t, err := time.ParseInLocation("01-02", text, boardLocation); err == nil {
+		t = t.AddDate(now.Year()-t.Year(), 0, 0)
+		if t.After(today) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t.Format(dateLayout), true
+	}
+
+	for _, l := range absoluteDateLayouts {
+		if t, err := time.ParseInLocation(l.layout, text, boardLocation); err == nil {
+			if l.withTime {
+				return t.Format(dateTimeLayout), true
+			}
+			return t.Format(dateLayout), true
+		}
+	}
+
+	return "", false
+}
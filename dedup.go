@@ -0,0 +1,118 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 게시글 번호 순서로 정렬합니다. 번호가 같으면(공지, 중복 수집된 글) 발견한 목록 page, page 안의 순서로 정렬해서
+// goroutine이 끝나는 순서와 상관없이 같은 데이터는 항상 같은 순서가 되도록 합니다.
+func sortPages(pages []pageInformation) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if a.pageNum != b.pageNum {
+			return a.pageNum < b.pageNum
+		}
+		if a.listPage != b.listPage {
+			return a.listPage < b.listPage
+		}
+		if a.row != b.row {
+			return a.row < b.row
+		}
+		return a.title < b.title
+	})
+}
+
+// 중복 제거에 사용하는 게시글 식별자. 공지처럼 번호가 없는 글은 링크로 구분합니다.
+func dedupKey(page pageInformation) string {
+	if page.pageNum != 0 {
+		return "num:" + strconv.Itoa(page.pageNum)
+	}
+	return "link:" + page.link
+}
+
+// 중복 제거에 사용할 필드를 정합니다. (-dedup-key num+category)
+// 필드 값을 모두 이어붙인 것이 식별자이고, 비워두면 dedupKey와 같이 번호(번호가 없으면 링크)로 구분합니다.
+// 공지처럼 번호가 없는 글의 num은 링크로 대신해서, 번호가 없는 글끼리 하나로 합쳐지지 않도록 합니다.
+func WithDedupKey(fields []outputField) Option {
+	return func(s *Scraper) {
+		s.dedupFields = fields
+	}
+}
+
+// fields로 만든 게시글 식별자. fields가 없으면 dedupKey입니다.
+func dedupKeyOf(page pageInformation, fields []outputField) string {
+	if len(fields) == 0 {
+		return dedupKey(page)
+	}
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		if f.name == "num" && page.pageNum == 0 {
+			parts[i] = "link:" + page.link
+			continue
+		}
+		parts[i] = f.name + ":" + f.value(page)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// sortPages로 정렬된 pages에서 같은 게시글은 처음 나온 것만 남깁니다. 같은 게시글인지는 fields로 구분합니다. (dedupKeyOf)
+func dedupPages(pages []pageInformation, fields []outputField) []pageInformation {
+	seen := map[string]bool{}
+	kept := pages[:0]
+	for _, page := range pages {
+		key := dedupKeyOf(page, fields)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, page)
+	}
+	return kept
+}
+
+// -dedup-key 값을 필드 이름으로 나눕니다. ("num+category" -> num, category)
+func parseDedupKey(spec string) []string {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+	names := []string{}
+	for _, name := range strings.Split(spec, "+") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// -dedup-key에 준 필드 목록
+func (c Config) dedupFields() []outputField {
+	fields := []outputField{}
+	for _, name := range parseDedupKey(c.DedupKey) {
+		if f, exists := c.findField(name); exists {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// -sort 값
+var sortOrders = []string{"num", "original"}
+
+// 결과를 게시판에 보이는 순서(page 순서, page 안의 행 순서)로 돌려줍니다. 끝나는 순서가 제각각인 worker의 결과를
+// 목록 page 번호와 행 번호로 다시 맞춥니다. 공지는 여러 page에 나오지만 중복 제거로 처음 나온 page의 것만 남습니다.
+func WithOriginalOrder(original bool) Option {
+	return func(s *Scraper) {
+		s.originalOrder = original
+	}
+}
+
+// 게시판 목록에 나온 순서(목록 page, page 안의 순서)대로 정렬합니다. -server-sort로 받은 순서나 -sort original을 지킬 때 사용합니다.
+func sortPagesByListing(pages []pageInformation) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if a.listPage != b.listPage {
+			return a.listPage < b.listPage
+		}
+		return a.row < b.row
+	})
+}
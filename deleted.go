@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 삭제된 게시글 자리에 남는 안내 문구들
+var deletedMarkers = []string{"삭제된 게시물", "삭제된 게시글", "삭제된 글", "삭제됨"}
+
+// 삭제되었지만 목록에 자리만 남아있는 행인지 확인합니다.
+// 삭제된 글은 번호는 그대로 두고 제목 자리에 안내 문구를 보여주거나, 행에 deleted class가 붙습니다.
+func isDeletedRow(row *goquery.Selection, title string) bool {
+	if row.HasClass("deleted") || row.HasClass("del") {
+		return true
+	}
+	for _, marker := range deletedMarkers {
+		if strings.HasPrefix(title, marker) {
+			return true
+		}
+	}
+	return false
+}
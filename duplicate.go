@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 제목 비교용 정규화: 앞뒤 공백 제거, 소문자 변환, 연속된 공백을 하나로 합칩니다.
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// 정규화된 제목이 같은 게시글들에 같은 dupGroup 번호를 붙이고, 찾은 그룹 수를 리턴합니다.
+// 중복이 없는 게시글은 dupGroup이 0으로 남습니다.
+// 그룹 번호는 pages 순서대로 처음 등장한 그룹부터 1, 2, ... 로 매겨집니다.
+func markDuplicateTitles(pages []pageInformation) int {
+	indexes := map[string][]int{}
+	order := []string{}
+
+	for i, page := range pages {
+		key := normalizeTitle(page.title)
+		if key == "" {
+			continue
+		}
+		if _, exists := indexes[key]; !exists {
+			order = append(order, key)
+		}
+		indexes[key] = append(indexes[key], i)
+	}
+
+	groups := 0
+	for _, key := range order {
+		if len(indexes[key]) < 2 {
+			continue
+		}
+		groups++
+		for _, i := range indexes[key] {
+			pages[i].dupGroup = groups
+		}
+	}
+
+	return groups
+}
+
+// -dup-titles 옵션에서 사용하는 post processor
+func duplicateTitleProcessor(pages []pageInformation) ([]pageInformation, error) {
+	groups := markDuplicateTitles(pages)
+	fmt.Println(fmt.Sprint(groups) + " duplicate title groups found")
+	return pages, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/transform"
+)
+
+const utf8BOM = "\xEF\xBB\xBF"
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// -encoding, -bom 조합이 올바른지 확인합니다.
+func checkEncoding(name string, bom bool) error {
+	switch strings.ToLower(name) {
+	case "utf-8", "utf8":
+		return nil
+	case "euc-kr", "euckr":
+		if bom {
+			return fmt.Errorf("-bom can only be used with utf-8 encoding")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported encoding %q (expected utf-8 or euc-kr)", name)
+	}
+}
+
+// 출력 인코딩에 맞게 w를 감싼 writer를 리턴합니다.
+// euc-kr 변환은 버퍼를 사용하므로 다 쓴 뒤 반드시 Close 해야 합니다.
+// euc-kr로 표현할 수 없는 문자는 에러 대신 대체 문자로 바뀝니다.
+func newOutputWriter(w io.Writer, name string, bom bool) (io.WriteCloser, error) {
+	if err := checkEncoding(name, bom); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.ToLower(name), "euc") {
+		return transform.NewWriter(w, encoding.ReplaceUnsupported(korean.EUCKR.NewEncoder())), nil
+	}
+
+	if bom {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+	return nopWriteCloser{w}, nil
+}
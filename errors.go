@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// Scrape가 리턴하는 에러의 분류. 라이브러리로 쓸 때 errors.Is로 확인해서 다시 시도할지, 알릴지, 무시할지 정할 수 있습니다.
+// 원래 에러도 감싸고 있으므로 errors.Is(err, context.Canceled), errors.As도 그대로 쓸 수 있습니다.
+var (
+	// ctx의 deadline(-timeout)이 지났습니다. -fail-fast에서 -timeout-per-page로 중단한 경우도 포함합니다.
+	ErrTimeout = errors.New("scrape timed out")
+	// 게시판이 로그인/captcha/차단 page를 돌려줬습니다.
+	ErrBlocked = errors.New("blocked by the board")
+	// 게시판에 게시글이 없어서 수집할 page가 없습니다.
+	ErrNoPages = errors.New("no pages to scrape")
+	// 수집을 끝까지 하지 못했습니다. (취소, -max-failures-abort, -fail-fast) 같이 리턴한 결과는 일부분입니다.
+	ErrPartial = errors.New("scrape is incomplete")
+)
+
+// 분류(kind)와 원래 에러를 같이 감싼 에러. 메시지는 원래 에러의 것을 그대로 씁니다.
+type scrapeError struct {
+	kind error
+	err  error
+}
+
+func (e *scrapeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *scrapeError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// 수집 도중에 멈춘 에러를 ErrPartial로 분류합니다. 차단과 timeout은 그 분류를 그대로 둡니다.
+func incompleteError(err error) error {
+	if err == nil || errors.Is(err, ErrBlocked) || errors.Is(err, ErrPartial) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return &scrapeError{kind: ErrPartial, err: err}
+}
+
+// deadline이 지나서 난 에러를 ErrTimeout으로 분류합니다.
+func timeoutError(err error) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	return &scrapeError{kind: ErrTimeout, err: err}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// -explain에서 행마다 보여줄 만큼의 예시 수
+const explainSamples = 3
+
+// -explain에서 확인하는 필드와 selector. attr이 있으면 text 대신 그 속성 값을 보여줍니다.
+var explainSelectors = []struct {
+	field    string
+	selector string
+	attr     string
+}{
+	{"title", titleSelector, ""},
+	{"link", titleSelector, "href"},
+	{"category", categorySelector, ""},
+	{"num", numSelector, ""},
+	{"user", userSelector, ""},
+	{"view", viewSelector, ""},
+	{"comments", commentsSelector, ""},
+	{"recommend", recommendSelector, ""},
+	{"thumbnail", thumbnailSelector, "src"},
+	{"date", dateSelector, ""},
+	{"no-result", noResultSelector, ""},
+}
+
+// -explain: 목록 page 하나를 가져와서 게시글 행과 행 안의 selector마다 몇 개의 요소를 찾았는지,
+// 처음 몇 개의 text(또는 속성)가 무엇인지 출력합니다. 다른 게시판에 맞춰 selector를 고칠 때 사용합니다.
+func (s *Scraper) explain(ctx context.Context, w io.Writer, pageNum int) error {
+	url := s.PageURL(pageNum)
+	doc, err := s.fetch(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	rows := doc.Find(listingRowSelector)
+	fmt.Fprintf(w, "%s\n\n%-10s %s: %d matches\n", url, "rows", listingRowSelector, rows.Length())
+	for _, sel := range explainSelectors {
+		matches := rows.Find(sel.selector)
+		fmt.Fprintf(w, "%-10s %s: %d matches\n", sel.field, sel.selector, matches.Length())
+		matches.Slice(0, min(explainSamples, matches.Length())).Each(func(i int, m *goquery.Selection) {
+			value := strings.TrimSpace(m.Text())
+			if sel.attr != "" {
+				value, _ = m.Attr(sel.attr)
+			}
+			fmt.Fprintf(w, "    [%d] %q\n", i, value)
+		})
+	}
+
+	base, _ := neturl.Parse(url)
+	pages, warnings := s.parse(doc, base, s.keepEmpty, s.referenceTime())
+	fmt.Fprintf(w, "\nparsed %d posts, %d warnings\n", len(pages), len(warnings))
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "    %s\n", warning)
+	}
+	return nil
+}
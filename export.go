@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/transform"
+)
+
+// 이전에 쓴 결과 파일을 다시 읽습니다. 형식은 확장자로 정합니다. (.json, .ndjson, 나머지는 CSV)
+// CSV는 영어/한국어 기본 헤더와 필드 이름을 모두 알아보고, 모르는 컬럼은 무시합니다.
+// euc-kr로 쓴 CSV는 encodingName을 euc-kr로 줘야 합니다.
+func readExport(path, encodingName string) ([]pageInformation, error) {
+	stream, err := openExportStream(path, encodingName)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	pages := []pageInformation{}
+	for {
+		page, err := stream.Next()
+		if err == io.EOF {
+			return pages, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+}
+
+// 결과 파일을 게시글 하나씩 읽습니다. 파일 전체를 메모리에 올리지 않으므로 큰 파일을 merge할 때 사용합니다.
+type exportStream struct {
+	path string
+	file *os.File
+	next func() (pageInformation, error) // 끝나면 io.EOF
+}
+
+// 다음 게시글을 리턴합니다. 더 없으면 io.EOF를 리턴합니다.
+func (s *exportStream) Next() (pageInformation, error) {
+	return s.next()
+}
+
+func (s *exportStream) Close() error {
+	return s.file.Close()
+}
+
+// readExport와 같은 규칙으로 path를 열어서, 게시글을 하나씩 읽는 exportStream을 리턴합니다.
+func openExportStream(path, encodingName string) (*exportStream, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	stream := &exportStream{path: path, file: file}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = stream.startJSON()
+	case ".ndjson", ".jsonl":
+		stream.startNDJSON()
+	default:
+		err = stream.startCSV(decodeExport(file, encodingName))
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return stream, nil
+}
+
+// JSON 배열의 원소를 하나씩 decode합니다.
+func (s *exportStream) startJSON() error {
+	dec := json.NewDecoder(s.file)
+	if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+		return fmt.Errorf("%s: not a JSON array of posts", s.path)
+	}
+	s.next = func() (pageInformation, error) {
+		var page pageInformation
+		if !dec.More() {
+			return page, io.EOF
+		}
+		if err := dec.Decode(&page); err != nil {
+			return page, fmt.Errorf("%s: %w", s.path, err)
+		}
+		return page, nil
+	}
+	return nil
+}
+
+func (s *exportStream) startNDJSON() {
+	scanner := bufio.NewScanner(s.file)
+	scanner.Buffer(nil, 1<<20)
+	line := 0
+	s.next = func() (pageInformation, error) {
+		var page pageInformation
+		for scanner.Scan() {
+			line++
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &page); err != nil {
+				return page, fmt.Errorf("%s:%d: %w", s.path, line, err)
+			}
+			return page, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return page, err
+		}
+		return page, io.EOF
+	}
+}
+
+func (s *exportStream) startCSV(r io.Reader) error {
+	reader := newCSVReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	columns := make([]string, len(header)) // 컬럼 번호 -> 필드 이름 (모르는 컬럼은 "")
+	for i, h := range header {
+		columns[i] = fieldForHeader(h)
+	}
+
+	s.next = func() (pageInformation, error) {
+		var page pageInformation
+		record, err := reader.Read()
+		if err == io.EOF {
+			return page, io.EOF
+		}
+		if err != nil {
+			return page, fmt.Errorf("%s: %w", s.path, err)
+		}
+
+		for i, value := range record {
+			if i >= len(columns) || columns[i] == "" {
+				continue
+			}
+			if err := page.setField(columns[i], value); err != nil {
+				line, _ := reader.FieldPos(i)
+				return page, fmt.Errorf("%s:%d: %s: %w", s.path, line, header[i], err)
+			}
+		}
+		return page, nil
+	}
+	return nil
+}
+
+// -encoding euc-kr로 쓴 파일을 UTF-8로 읽습니다.
+func decodeExport(r io.Reader, encodingName string) io.Reader {
+	if strings.HasPrefix(strings.ToLower(encodingName), "euc") {
+		return transform.NewReader(r, korean.EUCKR.NewDecoder())
+	}
+	return r
+}
+
+// BOM과 -csv-header-comment로 쓴 헤더 앞의 # 줄을 건너뛴 csv.Reader. 헤더 뒤의 행은 #으로 시작해도 값으로 읽습니다.
+func newCSVReader(r io.Reader) *csv.Reader {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(utf8BOM)); string(head) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+	for {
+		if head, _ := br.Peek(1); string(head) != "#" {
+			break
+		}
+		if _, err := br.ReadString('\n'); err != nil {
+			break
+		}
+	}
+	return csv.NewReader(br)
+}
+
+func readNDJSON(path string, r io.Reader) ([]pageInformation, error) {
+	pages := []pageInformation{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var page pageInformation
+		if err := json.Unmarshal(scanner.Bytes(), &page); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		pages = append(pages, page)
+	}
+	return pages, scanner.Err()
+}
+
+// CSV 헤더에 해당하는 필드 이름. 필드 이름, 영어 헤더, 한국어 헤더를 알아봅니다.
+func fieldForHeader(header string) string {
+	header = strings.TrimSpace(header)
+	for _, f := range outputFields {
+		if header == f.name || header == f.header || header == headerLanguages["ko"][f.name] {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// CSV에서 읽은 값 하나를 필드에 넣습니다.
+func (p *pageInformation) setField(name, value string) error {
+	var err error
+	switch name {
+	case "num":
+		p.pageNum, err = strconv.Atoi(value)
+	case "num_raw":
+		p.numRaw = value
+	case "title":
+		p.title = value
+	case "user":
+		p.user = value
+	case "view":
+		p.view, err = strconv.Atoi(value)
+	case "link":
+		p.link = value
+	case "date":
+		p.date = value
+	case "comments":
+		p.comments, err = strconv.Atoi(value)
+	case "recommend":
+		p.recommend, err = strconv.Atoi(value)
+	case "dup_group":
+		p.dupGroup, err = strconv.Atoi(value)
+	case "thumbnail":
+		p.thumbnail = value
+	case "image_file":
+		p.imageFile = value
+	case "category":
+		p.category = value
+	case "deleted":
+		p.deleted, err = strconv.ParseBool(value)
+	case "new":
+		p.isNew, err = strconv.ParseBool(value)
+	case "source":
+		p.source = value
+	case "body_len":
+		p.bodyLen, err = strconv.Atoi(value)
+	case "is_new":
+		p.newBadge, err = strconv.ParseBool(value)
+	case "is_hot":
+		p.hot, err = strconv.ParseBool(value)
+	case "has_image":
+		p.hasImage, err = strconv.ParseBool(value)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 게시판마다 다른 칸을 코드 수정 없이 수집하기 위한 추가 필드 (-extra-field "name=selector" 또는 "name=selector@attr")
+// selector는 게시글 행(tr) 안에서 찾고, 첫 번째로 찾은 요소의 text(또는 attr 속성 값)를 사용합니다.
+type extraField struct {
+	name     string
+	selector string
+	attr     string // 비어 있으면 text
+}
+
+func parseExtraField(spec string) (extraField, error) {
+	name, selector, found := strings.Cut(spec, "=")
+	name, selector = strings.TrimSpace(name), strings.TrimSpace(selector)
+	if !found || name == "" || selector == "" {
+		return extraField{}, fmt.Errorf("%q is not in name=selector or name=selector@attr form", spec)
+	}
+
+	f := extraField{name: name, selector: selector}
+	if i := strings.LastIndex(selector, "@"); i >= 0 {
+		f.selector, f.attr = strings.TrimSpace(selector[:i]), strings.TrimSpace(selector[i+1:])
+		if f.selector == "" || f.attr == "" {
+			return extraField{}, fmt.Errorf("%q is not in name=selector or name=selector@attr form", spec)
+		}
+	}
+	if err := checkSelector(f.selector); err != nil {
+		return extraField{}, fmt.Errorf("%q: invalid selector: %w", spec, err)
+	}
+	return f, nil
+}
+
+// 목록 page에서 추가 필드를 수집할 selector를 정합니다. 결과 컬럼은 fields의 순서대로 기본 컬럼 뒤에 붙습니다.
+func WithExtraFields(fields []extraField) Option {
+	return func(s *Scraper) {
+		s.extraFields = fields
+	}
+}
+
+// parsePage가 만든 pages의 row 순서로 게시글 행을 다시 찾아서 추가 필드를 채웁니다.
+func collectExtraFields(doc *goquery.Document, pages []pageInformation, fields []extraField) {
+	if len(fields) == 0 {
+		return
+	}
+	rows := doc.Find(listingRowSelector)
+	for i := range pages {
+		row := rows.Eq(pages[i].row)
+		pages[i].extra = map[string]string{}
+		for _, f := range fields {
+			sel := row.Find(f.selector).First()
+			value := strings.TrimSpace(sel.Text())
+			if f.attr != "" {
+				value, _ = sel.Attr(f.attr)
+			}
+			pages[i].extra[f.name] = value
+		}
+	}
+}
+
+// 설정의 -extra-field 목록. Validate를 통과한 설정이라고 가정하고 잘못된 항목은 건너뜁니다.
+func (c Config) extraFields() []extraField {
+	fields := []extraField{}
+	for _, spec := range c.ExtraFields {
+		if f, err := parseExtraField(spec); err == nil {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// 기본 컬럼과 -extra-field 컬럼에서 name을 찾습니다.
+func (c Config) findField(name string) (outputField, bool) {
+	if f, exists := findOutputField(name); exists {
+		return f, true
+	}
+	for _, extra := range c.extraFields() {
+		if extra.name == name {
+			return extra.outputField(), true
+		}
+	}
+	return outputField{}, false
+}
+
+func (f extraField) outputField() outputField {
+	return outputField{f.name, f.name, func(p pageInformation) string { return p.extra[f.name] }}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Fetcher는 목록 page URL 하나를 받아서 파싱할 HTML 문서를 돌려줍니다.
+// 기본은 net/http로 요청하는 httpFetcher이고, 테스트나 headless browser처럼 다른 방법으로
+// page를 가져올 때는 WithFetcher로 바꿉니다.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (*goquery.Document, error)
+}
+
+// 목록 page를 가져올 Fetcher를 정합니다. 정하지 않으면 Scraper의 http.Client로 요청합니다.
+// 기본 Fetcher가 아니면 Scrape를 시작할 때 Preflight를 건너뜁니다.
+func WithFetcher(fetcher Fetcher) Option {
+	return func(s *Scraper) {
+		s.fetcher = fetcher
+	}
+}
+
+// Scraper의 rate limit, adaptive 조절, audit log를 거쳐서 GET 요청을 보내는 기본 Fetcher
+type httpFetcher struct {
+	s *Scraper
+}
+
+func (f httpFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	res, err := f.s.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &statusError{res: res}
+	}
+
+	// JSON이나 text로 된 에러 응답을 파싱하면 selector가 아무것도 찾지 못해서 빈 게시판처럼 보이므로,
+	// HTML이 아니면 파싱하기 전에 에러로 돌려줍니다.
+	body := bufio.NewReader(res.Body)
+	head, _ := body.Peek(512)
+	contentType := res.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(head)
+	}
+	if !isHTMLContentType(contentType) {
+		if f.s.verbose {
+			log.Printf("%s: first bytes of the response: %q\n", url, head[:min(len(head), 200)])
+		}
+		return nil, &nonHTMLError{url: url, contentType: contentType}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	// redirect되었는지 알 수 있도록 최종 URL을 기록합니다.
+	doc.Url = res.Request.URL
+	return doc, nil
+}
+
+// HTML이 아닌 응답을 받았을 때의 에러. 같은 요청을 다시 보내도 결과가 같을 가능성이 높아서 재시도하지 않습니다.
+type nonHTMLError struct {
+	url         string
+	contentType string
+}
+
+func (e *nonHTMLError) Error() string {
+	return fmt.Sprintf("%s returned Content-Type %q instead of HTML (run with -v to see the start of the response)", e.url, e.contentType)
+}
+
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// 다시 요청해볼 만한 에러인지 확인합니다. 차단 page, HTML이 아닌 응답, 따라가지 않은 redirect는 재시도해도 소용이 없습니다.
+func retryable(err error) bool {
+	var nonHTML *nonHTMLError
+	return !isBlocked(err) && !errors.As(err, &nonHTML) && !isRedirectError(err)
+}
+
+// Fetcher로 문서를 받고, 로그인/captcha/차단 page라면 blockedError를 리턴합니다.
+func (s *Scraper) fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	var doc *goquery.Document
+	var err error
+	if s.fetcher != nil {
+		doc, err = s.fetcher.Fetch(ctx, url)
+	} else {
+		doc, err = httpFetcher{s: s}.Fetch(ctx, url)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.block.check(url, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
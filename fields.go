@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 출력 컬럼 하나. name은 JSON 필드 이름과 같고, header는 CSV의 기본(영어) 헤더입니다.
+type outputField struct {
+	name   string
+	header string
+	value  func(p pageInformation) string
+}
+
+// 출력할 수 있는 전체 컬럼 (CSV 컬럼 순서). 새 필드를 추가할 때는 여기와 headerLanguages 양쪽에 추가합니다.
+var outputFields = []outputField{
+	{"num", "No.", func(p pageInformation) string { return strconv.Itoa(p.pageNum) }},
+	{"num_raw", "Raw No.", func(p pageInformation) string { return p.numRaw }},
+	{"title", "Title", func(p pageInformation) string { return p.title }},
+	{"user", "User", func(p pageInformation) string { return p.user }},
+	{"view", "View", func(p pageInformation) string { return strconv.Itoa(p.view) }},
+	{"link", "Link", func(p pageInformation) string { return p.link }},
+	{"date", "Date", func(p pageInformation) string { return p.date }},
+	{"comments", "Comments", func(p pageInformation) string { return strconv.Itoa(p.comments) }},
+	{"recommend", "Recommend", func(p pageInformation) string { return strconv.Itoa(p.recommend) }},
+	{"dup_group", "Dup Group", func(p pageInformation) string { return strconv.Itoa(p.dupGroup) }},
+	{"thumbnail", "Thumbnail", func(p pageInformation) string { return p.thumbnail }},
+	{"image_file", "Image File", func(p pageInformation) string { return p.imageFile }},
+	{"category", "Category", func(p pageInformation) string { return p.category }},
+	{"deleted", "Deleted", func(p pageInformation) string { return strconv.FormatBool(p.deleted) }},
+	{"new", "New", func(p pageInformation) string { return strconv.FormatBool(p.isNew) }},
+	{"source", "Source", func(p pageInformation) string { return p.source }},
+	{"body_len", "Body Length", func(p pageInformation) string { return strconv.Itoa(p.bodyLen) }},
+	{"is_new", "New Badge", func(p pageInformation) string { return strconv.FormatBool(p.newBadge) }},
+	{"is_hot", "Hot", func(p pageInformation) string { return strconv.FormatBool(p.hot) }},
+	{"has_image", "Has Image", func(p pageInformation) string { return strconv.FormatBool(p.hasImage) }},
+}
+
+// -lang으로 고를 수 있는 헤더 모음
+var headerLanguages = map[string]map[string]string{
+	"en": {},
+	"ko": {
+		"num":        "번호",
+		"num_raw":    "번호 원문",
+		"title":      "제목",
+		"user":       "글쓴이",
+		"view":       "조회",
+		"link":       "링크",
+		"date":       "등록일",
+		"comments":   "댓글",
+		"recommend":  "추천",
+		"dup_group":  "중복 그룹",
+		"thumbnail":  "썸네일",
+		"image_file": "이미지 파일",
+		"category":   "말머리",
+		"deleted":    "삭제됨",
+		"new":        "새 글",
+		"source":     "출처",
+		"body_len":   "본문 길이",
+		"is_new":     "새 글 표시",
+		"is_hot":     "인기",
+		"has_image":  "이미지",
+	},
+}
+
+func findOutputField(name string) (outputField, bool) {
+	for _, f := range outputFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return outputField{}, false
+}
+
+// 출력할 수 있는 전체 필드 이름 (outputFields 순서)
+func outputFieldNames() []string {
+	names := []string{}
+	for _, f := range outputFields {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// 필드 이름 -> 헤더 매핑 옵션 (-headers "title=제목,view=조회수")
+type headerMap map[string]string
+
+func (m *headerMap) String() string {
+	pairs := []string{}
+	for name, header := range *m {
+		pairs = append(pairs, name+"="+header)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m *headerMap) Set(value string) error {
+	if *m == nil {
+		*m = headerMap{}
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, header, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("%q is not in field=Header form", pair)
+		}
+		(*m)[strings.TrimSpace(name)] = strings.TrimSpace(header)
+	}
+	return nil
+}
+
+// 설정에 따라 CSV에 쓸 컬럼 목록을 만듭니다. 기본 컬럼 뒤에 켜진 옵션의 컬럼, -extra-field 컬럼 순서로 붙습니다.
+// -fields를 주면 그 컬럼만 준 순서대로 씁니다.
+func (c Config) csvFields() []outputField {
+	if len(c.Fields) > 0 {
+		fields := []outputField{}
+		for _, name := range c.Fields {
+			f, _ := c.findField(name)
+			fields = append(fields, f)
+		}
+		return fields
+	}
+
+	enabled := map[string]bool{
+		"num":        true,
+		"num_raw":    c.IncludeRawNum,
+		"title":      true,
+		"user":       true,
+		"view":       true,
+		"link":       true,
+		"date":       c.Dates,
+		"comments":   c.Counts,
+		"recommend":  c.Counts,
+		"dup_group":  c.DupTitles,
+		"thumbnail":  c.Thumbnails,
+		"image_file": c.DownloadImages != "",
+		"category":   c.Categories,
+		"deleted":    c.IncludeDeleted,
+		"new":        c.SeenDB != "",
+		"source":     c.Mode != "normal",
+		"body_len":   c.IncludeBodyLength,
+		"is_new":     c.IncludeBadges,
+		"is_hot":     c.IncludeBadges,
+		"has_image":  c.IncludeBadges,
+	}
+
+	fields := []outputField{}
+	for _, f := range outputFields {
+		if enabled[f.name] {
+			fields = append(fields, f)
+		}
+	}
+	for _, extra := range c.extraFields() {
+		fields = append(fields, extra.outputField())
+	}
+	return fields
+}
+
+// 컬럼 헤더: -headers로 지정한 값 > -lang 헤더 > 기본(영어) 헤더 순서로 정합니다.
+func (c Config) csvHeader(f outputField) string {
+	if header, exists := c.Headers[f.name]; exists {
+		return header
+	}
+	if header, exists := headerLanguages[c.Lang][f.name]; exists {
+		return header
+	}
+	return f.header
+}
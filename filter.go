@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// -category 옵션에서 사용하는 post processor. 말머리가 categories 중 하나와 같은 게시글만 남깁니다. (대소문자 무시)
+// 수집한 게시글 중 말머리가 있는 글이 하나도 없으면 게시판이 말머리를 쓰지 않는 것이므로,
+// 전부 지워버리는 대신 경고를 남기고 필터를 적용하지 않습니다.
+func categoryFilter(categories []string) func([]pageInformation) ([]pageInformation, error) {
+	wanted := map[string]bool{}
+	for _, category := range categories {
+		wanted[strings.ToLower(cleanCategory(category))] = true
+	}
+
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		hasCategory := false
+		for _, page := range pages {
+			if page.category != "" {
+				hasCategory = true
+				break
+			}
+		}
+		if !hasCategory && len(pages) > 0 {
+			log.Println("Warning: no post has a category on this board, -category filter is ignored")
+			return pages, nil
+		}
+
+		kept := []pageInformation{}
+		for _, page := range pages {
+			if wanted[strings.ToLower(page.category)] {
+				kept = append(kept, page)
+			}
+		}
+		fmt.Printf("category filter: kept %d of %d posts\n", len(kept), len(pages))
+		return kept, nil
+	}
+}
+
+// 제목 하나가 패턴에 맞는지 확인하는 함수를 만듭니다.
+// useRegex면 패턴을 정규식으로, 아니면 대소문자를 무시한 부분 문자열로 취급합니다.
+func titleMatcher(patterns []string, useRegex bool) (func(title string) bool, error) {
+	if useRegex {
+		regexps := []*regexp.Regexp{}
+		for _, pattern := range patterns {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+			regexps = append(regexps, re)
+		}
+		return func(title string) bool {
+			for _, re := range regexps {
+				if re.MatchString(title) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+
+	keywords := []string{}
+	for _, pattern := range patterns {
+		keywords = append(keywords, strings.ToLower(pattern))
+	}
+	return func(title string) bool {
+		title = strings.ToLower(title)
+		for _, keyword := range keywords {
+			if strings.Contains(title, keyword) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// -match, -exclude 옵션에서 사용하는 post processor.
+// match가 있으면 그 중 하나라도 제목에 포함된 글만 남기고, 그 다음 exclude 중 하나라도 포함된 글을 지웁니다.
+// 두 조건에 모두 걸리면 exclude가 우선합니다.
+func keywordFilter(match, exclude []string, useRegex bool) (func([]pageInformation) ([]pageInformation, error), error) {
+	included, err := titleMatcher(match, useRegex)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := titleMatcher(exclude, useRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		kept := []pageInformation{}
+		notMatched, dropped := 0, 0
+		for _, page := range pages {
+			if len(match) > 0 && !included(page.title) {
+				notMatched++
+				continue
+			}
+			if len(exclude) > 0 && excluded(page.title) {
+				dropped++
+				continue
+			}
+			kept = append(kept, page)
+		}
+		fmt.Printf("keyword filter: kept %d of %d posts (%d not matched, %d excluded)\n", len(kept), len(pages), notMatched, dropped)
+		return kept, nil
+	}, nil
+}
+
+// -min-views, -min-comments, -min-recommend 옵션에서 사용하는 post processor.
+// 값이 0보다 큰 조건을 모두 만족하는 게시글만 남기고, 조건별로 몇 개가 빠졌는지 출력합니다.
+// 한 게시글이 여러 조건에 걸리면 처음 걸린 조건(조회수, 댓글, 추천 순서)으로 셉니다.
+func countFilter(minViews, minComments, minRecommend int) func([]pageInformation) ([]pageInformation, error) {
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		kept := []pageInformation{}
+		views, comments, recommend := 0, 0, 0
+		for _, page := range pages {
+			switch {
+			case page.view < minViews:
+				views++
+			case page.comments < minComments:
+				comments++
+			case page.recommend < minRecommend:
+				recommend++
+			default:
+				kept = append(kept, page)
+			}
+		}
+		fmt.Printf("count filter: kept %d of %d posts (%d below -min-views, %d below -min-comments, %d below -min-recommend)\n", len(kept), len(pages), views, comments, recommend)
+		return kept, nil
+	}
+}
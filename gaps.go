@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// -report-gaps에서 stdout에 출력하는 최대 구간 수. 나머지는 -report-gaps-file로 확인합니다.
+const maxPrintedGaps = 50
+
+// 게시글 번호는 1씩 증가하므로, 수집한 가장 작은 번호와 가장 큰 번호 사이에서 빠진 번호는 삭제된 게시글입니다.
+// 빠진 번호 구간을 lo 오름차순으로 리턴합니다. 공지처럼 번호가 없는(0) 게시글은 건너뜁니다.
+func postGaps(pages []pageInformation) (gaps []seenSpan, lo, hi int) {
+	nums := []int{}
+	for _, page := range pages {
+		if page.pageNum > 0 {
+			nums = append(nums, page.pageNum)
+		}
+	}
+	if len(nums) == 0 {
+		return nil, 0, 0
+	}
+	sort.Ints(nums)
+
+	gaps = []seenSpan{}
+	for i := 1; i < len(nums); i++ {
+		if nums[i] > nums[i-1]+1 {
+			gaps = append(gaps, seenSpan{nums[i-1] + 1, nums[i] - 1})
+		}
+	}
+	return gaps, nums[0], nums[len(nums)-1]
+}
+
+func gapCount(gaps []seenSpan) int {
+	count := 0
+	for _, gap := range gaps {
+		count += gap.hi - gap.lo + 1
+	}
+	return count
+}
+
+// 빠진 번호 구간과 개수를 출력합니다.
+func printGaps(w io.Writer, gaps []seenSpan, lo, hi int) {
+	fmt.Fprintf(w, "gaps: %d post numbers missing in %d ranges between %d and %d\n", gapCount(gaps), len(gaps), lo, hi)
+	for i, gap := range gaps {
+		if i == maxPrintedGaps {
+			fmt.Fprintf(w, "  ... and %d more ranges (write them all with -report-gaps-file)\n", len(gaps)-i)
+			break
+		}
+		if gap.lo == gap.hi {
+			fmt.Fprintf(w, "  %d (1)\n", gap.lo)
+		} else {
+			fmt.Fprintf(w, "  %d-%d (%d)\n", gap.lo, gap.hi, gap.hi-gap.lo+1)
+		}
+	}
+}
+
+// 빠진 번호 구간을 from,to,missing 컬럼의 CSV로 씁니다.
+func writeGaps(w io.Writer, gaps []seenSpan) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"from", "to", "missing"})
+	for _, gap := range gaps {
+		cw.Write([]string{strconv.Itoa(gap.lo), strconv.Itoa(gap.hi), strconv.Itoa(gap.hi - gap.lo + 1)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// 게시글을 결과에서 빼는 옵션. 이 옵션들이 켜져 있으면 빠진 번호가 삭제된 게시글이 아닐 수 있습니다.
+func (c Config) gapFilters() []string {
+	filters := []string{}
+	add := func(on bool, name string) {
+		if on {
+			filters = append(filters, name)
+		}
+	}
+	add(len(c.CategoryFilter) > 0, "-category")
+	add(len(c.Match) > 0, "-match")
+	add(len(c.Exclude) > 0, "-exclude")
+	add(c.MinViews > 0 || c.MinComments > 0 || c.MinRecommend > 0, "-min-views/-min-comments/-min-recommend")
+	add(c.Limit > 0, "-limit")
+	add(c.Sample != "" || c.SampleN > 0, "-sample")
+	add(c.PostProcess != "", "-post-process")
+	add(c.Watermark != "", "-watermark")
+	add(c.Mode != "normal", "-mode "+c.Mode)
+	return filters
+}
+
+// -report-gaps를 처리합니다. 필터나 실패한 page 때문에 생긴 구간은 삭제와 구분할 수 없으므로 경고합니다.
+func reportGaps(w io.Writer, pages []pageInformation, cfg Config, failed []int) error {
+	gaps, lo, hi := postGaps(pages)
+	if filters := cfg.gapFilters(); len(filters) > 0 {
+		fmt.Fprintf(w, "gaps: warning: %s removed posts from the results, so some gaps are filtered posts rather than deletions\n", strings.Join(filters, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(w, "gaps: warning: posts of the %d failed pages show up as gaps\n", len(failed))
+	}
+
+	if hi == 0 {
+		fmt.Fprintln(w, "gaps: no numbered posts in the results")
+		return nil
+	}
+
+	if cfg.ReportGapsFile != "" {
+		if err := writeFileAtomic(cfg.ReportGapsFile, func(file io.Writer) error { return writeGaps(file, gaps) }); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "gaps: %d post numbers missing in %d ranges between %d and %d, written to %s\n", gapCount(gaps), len(gaps), lo, hi, cfg.ReportGapsFile)
+		return nil
+	}
+	printGaps(w, gaps, lo, hi)
+	return nil
+}
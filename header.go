@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// 모든 요청에 추가로 보낼 header를 정합니다. Accept-Language 등 다른 옵션이 정한 header보다 나중에 적용되므로,
+// 같은 이름이 있으면 headers의 값이 이깁니다.
+func WithHeaders(headers http.Header) Option {
+	return func(s *Scraper) {
+		s.headers = headers
+	}
+}
+
+// -header "Key: Value"를 이름과 값으로 나눕니다. 이름은 canonical 형태(x-requested-with -> X-Requested-With)로 바꿉니다.
+func parseHeader(raw string) (string, string, error) {
+	name, value, found := strings.Cut(raw, ":")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !found || name == "" {
+		return "", "", fmt.Errorf("%q is not in \"Key: Value\" form", raw)
+	}
+	for _, r := range name {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`()<>@,;:\"/[]?={}`, r) {
+			return "", "", fmt.Errorf("%q is not a valid header name", name)
+		}
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return "", "", fmt.Errorf("header %s: value must not contain a line break", name)
+	}
+	name = textproto.CanonicalMIMEHeaderKey(name)
+	// net/http는 Header의 Host를 무시하고 URL의 host를 보냅니다.
+	if name == "Host" {
+		return "", "", fmt.Errorf("the Host header cannot be set with -header")
+	}
+	return name, value, nil
+}
+
+// -header 값들을 http.Header로 바꿉니다. 같은 이름을 여러 번 주면 값을 모두 보냅니다.
+// Validate를 통과한 설정에서만 호출합니다.
+func (c Config) requestHeaders() http.Header {
+	headers := http.Header{}
+	for _, raw := range c.RequestHeaders {
+		name, value, _ := parseHeader(raw)
+		headers.Add(name, value)
+	}
+	return headers
+}
+
+// req에 추가 header를 붙입니다.
+func (s *Scraper) setHeaders(req *http.Request) {
+	for name, values := range s.headers {
+		req.Header[name] = values
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// -health-addr: -interval로 오래 도는 프로세스의 상태를 HTTP로 알려줍니다. (Kubernetes의 liveness, readiness probe 용)
+//   - /healthz: 프로세스가 살아 있으면 항상 200
+//   - /readyz: 실패한 page 없이 끝난 실행이 한 번이라도 있으면 200, 아니면 503
+//   - /lastrun: 마지막 실행의 시각, 행 수, 실패한 page 수, 에러를 JSON으로 (아직 끝난 실행이 없으면 404)
+type healthState struct {
+	mu    sync.Mutex
+	ready bool
+	last  *lastRun
+}
+
+// /lastrun의 응답
+type lastRun struct {
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+	Rows        int       `json:"rows"`
+	FailedPages int       `json:"failed_pages"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// 끝난 실행 하나를 기록합니다.
+func (h *healthState) record(run lastRun) {
+	if run.FailedPages > 0 && run.Error == "" {
+		run.Error = fmt.Sprintf("%d pages failed", run.FailedPages)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = &run
+	h.ready = h.ready || run.Error == ""
+}
+
+func (h *healthState) register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		ready, last := h.ready, h.last
+		h.mu.Unlock()
+		if !ready {
+			message := "no successful run yet"
+			if last != nil {
+				message += ": " + last.Error
+			}
+			http.Error(w, message, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/lastrun", func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		last := h.last
+		h.mu.Unlock()
+		if last == nil {
+			http.Error(w, "no run has finished yet", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(last)
+	})
+}
+
+// cfg.HealthAddr에서 health endpoint를 제공합니다. -pprof-addr와 같은 주소면 그 server에 endpoint만 추가합니다.
+func startHealthServer(cfg Config, h *healthState) error {
+	if cfg.HealthAddr == cfg.PprofAddr {
+		h.register(http.DefaultServeMux)
+		return nil
+	}
+	listener, err := net.Listen("tcp", cfg.HealthAddr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	h.register(mux)
+	log.Printf("health: serving /healthz, /readyz and /lastrun on http://%s\n", listener.Addr())
+	go http.Serve(listener, mux)
+	return nil
+}
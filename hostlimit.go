@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// host마다 동시에 보내는 요청 수를 n개로 제한합니다. (-max-per-host, 0이면 제한하지 않음)
+// -workers는 전체 worker 수라서, 게시판이 여러 개이거나 -render, -download-images처럼 다른 요청이 섞이면
+// host 하나가 받는 동시 요청 수와 다를 수 있습니다. 요청은 응답 body를 닫을 때까지 진행 중으로 셉니다.
+func WithMaxPerHost(n int) Option {
+	return func(s *Scraper) {
+		if n > 0 {
+			s.hosts = &hostLimiter{max: n, slots: map[string]chan struct{}{}}
+		} else {
+			s.hosts = nil
+		}
+	}
+}
+
+// hostname마다의 semaphore
+type hostLimiter struct {
+	max   int
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// host에 요청을 보낼 자리가 날 때까지 기다립니다. 리턴한 release를 한 번 호출해서 자리를 돌려줘야 합니다.
+// 기다리는 도중에 ctx가 취소되면 ctx의 에러를 리턴합니다.
+func (l *hostLimiter) acquire(ctx context.Context, host string) (release func(), err error) {
+	l.mu.Lock()
+	slots, exists := l.slots[host]
+	if !exists {
+		slots = make(chan struct{}, l.max)
+		l.slots[host] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-slots }) }, nil
+}
+
+// body를 닫을 때 host의 자리를 돌려주는 응답 body
+type hostSlotBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *hostSlotBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
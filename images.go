@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// 썸네일 파일 이름: 게시글 번호 + 원래 확장자 (예: 12345.jpg)
+// 공지처럼 번호가 없는 게시글은 링크의 마지막 경로를 번호 대신 사용합니다.
+func imageFileName(page pageInformation) string {
+	name := strconv.Itoa(page.pageNum)
+	if page.pageNum == 0 {
+		u, err := neturl.Parse(page.link)
+		if err != nil || path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+			return ""
+		}
+		name = "notice-" + path.Base(u.Path)
+	}
+
+	ext := ".jpg"
+	if u, err := neturl.Parse(page.thumbnail); err == nil && path.Ext(u.Path) != "" {
+		ext = path.Ext(u.Path)
+	}
+	return name + ext
+}
+
+// 게시글마다 썸네일을 s.imageDir에 내려받고 저장한 경로를 imageFile에 기록합니다.
+// 이미 내려받은 파일은 다시 받지 않고, 실패한 이미지는 로그만 남기고 건너뜁니다.
+// 요청은 page 수집과 같은 rate limit과 worker 수를 따릅니다.
+func (s *Scraper) downloadImages(ctx context.Context, pages []pageInformation) error {
+	if err := os.MkdirAll(s.imageDir, 0755); err != nil {
+		return fmt.Errorf("creating image directory: %w", err)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := 0
+
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := s.downloadImage(ctx, &pages[i]); err != nil {
+					log.Printf("image for post %d: %v\n", pages[i].pageNum, err)
+					mu.Lock()
+					failed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range pages {
+		if pages[i].thumbnail != "" {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failed > 0 {
+		fmt.Printf("%d images could not be downloaded\n", failed)
+	}
+	return nil
+}
+
+func (s *Scraper) downloadImage(ctx context.Context, page *pageInformation) error {
+	name := imageFileName(*page)
+	if name == "" {
+		return fmt.Errorf("no post number or link to name the file after")
+	}
+	dest := filepath.Join(s.imageDir, name)
+
+	if _, err := os.Stat(dest); err == nil {
+		page.imageFile = dest
+		return nil
+	}
+
+	res, err := s.get(ctx, page.thumbnail)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", page.thumbnail, res.StatusCode)
+	}
+
+	// 중간에 실패해도 깨진 파일이 남지 않도록 임시 파일에 받은 뒤 이름을 바꿉니다.
+	tmp, err := os.CreateTemp(s.imageDir, name+".*.part")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, res.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	page.imageFile = dest
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// pageInformation은 필드가 unexported라서 JSON 변환용 구조체를 따로 둡니다.
+type pageJSON struct {
+	Num       int    `json:"num"`
+	NumRaw    string `json:"num_raw,omitempty"`
+	Title     string `json:"title"`
+	User      string `json:"user"`
+	View      int    `json:"view"`
+	Link      string `json:"link"`
+	Date      string `json:"date,omitempty"`
+	Comments  int    `json:"comments"`
+	Recommend int    `json:"recommend"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	ImageFile string `json:"image_file,omitempty"`
+	Category  string `json:"category,omitempty"`
+	Deleted   bool   `json:"deleted,omitempty"`
+	DupGroup  int    `json:"dup_group,omitempty"`
+	New       bool   `json:"new,omitempty"`
+	Source    string `json:"source,omitempty"`
+	BodyLen   int    `json:"body_len,omitempty"`
+	IsNew     bool   `json:"is_new,omitempty"`
+	IsHot     bool   `json:"is_hot,omitempty"`
+	HasImage  bool   `json:"has_image,omitempty"`
+
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+func (p pageInformation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pageJSON{
+		Num:       p.pageNum,
+		NumRaw:    p.numRaw,
+		Title:     p.title,
+		User:      p.user,
+		View:      p.view,
+		Link:      p.link,
+		Date:      p.date,
+		Comments:  p.comments,
+		Recommend: p.recommend,
+		Thumbnail: p.thumbnail,
+		ImageFile: p.imageFile,
+		Category:  p.category,
+		Deleted:   p.deleted,
+		DupGroup:  p.dupGroup,
+		New:       p.isNew,
+		Source:    p.source,
+		BodyLen:   p.bodyLen,
+		IsNew:     p.newBadge,
+		IsHot:     p.hot,
+		HasImage:  p.hasImage,
+		Extra:     p.extra,
+	})
+}
+
+func (p *pageInformation) UnmarshalJSON(data []byte) error {
+	var v pageJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*p = pageInformation{
+		pageNum:   v.Num,
+		numRaw:    v.NumRaw,
+		title:     v.Title,
+		user:      v.User,
+		view:      v.View,
+		link:      v.Link,
+		date:      v.Date,
+		comments:  v.Comments,
+		recommend: v.Recommend,
+		thumbnail: v.Thumbnail,
+		imageFile: v.ImageFile,
+		category:  v.Category,
+		deleted:   v.Deleted,
+		dupGroup:  v.DupGroup,
+		isNew:     v.New,
+		source:    v.Source,
+		bodyLen:   v.BodyLen,
+		newBadge:  v.IsNew,
+		hot:       v.IsHot,
+		hasImage:  v.HasImage,
+		extra:     v.Extra,
+	}
+	return nil
+}
+
+// 결과 전체를 하나의 JSON 배열로 씁니다. compact가 아니면 사람이 읽기 쉽게 들여쓰기합니다.
+func writeJSON(w io.Writer, pages []pageInformation, compact bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if !compact {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(pages)
+}
+
+// 게시글 하나당 JSON 한 줄씩 씁니다. (NDJSON, 항상 compact)
+func writeNDJSON(w io.Writer, pages []pageInformation) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, page := range pages {
+		if err := enc.Encode(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
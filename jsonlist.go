@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 목록 page를 HTML 대신 JSON endpoint에서 받습니다. (-json-endpoint)
+// template의 {base}, {n}은 -page-template과 같은 방식으로 채웁니다. ("{base}/api/list?page={n}")
+// 마지막 page는 지금처럼 HTML 목록으로 찾고, page마다의 게시글만 JSON에서 읽습니다.
+// listKey는 게시글 배열이 있는 곳으로, 응답이 배열이면 비워두고 객체라면 "posts"나 "data.list"처럼 점으로 이은 key입니다.
+func WithJSONEndpoint(template, listKey string) Option {
+	return func(s *Scraper) {
+		s.jsonEndpoint = template
+		s.jsonListKey = listKey
+	}
+}
+
+// 목록 page pageNum의 게시글을 -json-endpoint가 있으면 JSON에서, 없으면 HTML에서 읽습니다.
+func (s *Scraper) getListingPage(ctx context.Context, pageNum int, retry bool) ([]pageInformation, []parseWarning, error) {
+	if s.jsonEndpoint != "" {
+		return s.getJSONPage(ctx, pageNum, retry)
+	}
+	if s.snapshotSource != "" {
+		return s.readSnapshotPage(pageNum)
+	}
+
+	url := s.PageURL(pageNum)
+	doc, err := s.fetchListing(ctx, url, retry)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.snapshotDir != "" {
+		if err := s.saveSnapshot(pageNum, url, doc); err != nil {
+			return nil, nil, err
+		}
+	}
+	pages, warnings := s.parseListing(doc, url, s.referenceTime())
+	warnings, err = s.checkViewColumn(doc, url, warnings)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pages, warnings, nil
+}
+
+// JSON endpoint의 page n URL
+func (s *Scraper) jsonPageURL(n int) string {
+	return expandPageTemplate(s.jsonEndpoint, s.baseURL, s.pageParams(), strconv.Itoa(n))
+}
+
+// JSON endpoint에서 목록 page pageNum을 받아서 HTML 목록과 같은 게시글로 바꿉니다.
+// 게시글 하나는 -format json의 게시글과 같은 key(num, title, user, view, link, date, ...)로 된 객체입니다.
+func (s *Scraper) getJSONPage(ctx context.Context, pageNum int, retry bool) ([]pageInformation, []parseWarning, error) {
+	url := s.jsonPageURL(pageNum)
+	var data []byte
+	fetch := func() error {
+		fmt.Println("Requesting from : ", url)
+		var err error
+		data, err = s.fetchJSON(ctx, url)
+		return err
+	}
+	var err error
+	if retry {
+		err = s.withRetries(ctx, fetch)
+	} else {
+		err = fetch()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	base, _ := neturl.Parse(url)
+	return parseJSONPage(data, s.jsonListKey, base, s.keepEmpty, s.referenceTime())
+}
+
+func (s *Scraper) fetchJSON(ctx context.Context, url string) ([]byte, error) {
+	res, err := s.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &statusError{res: res}
+	}
+	return io.ReadAll(res.Body)
+}
+
+// JSON 목록 응답을 게시글로 바꿉니다. 링크와 썸네일은 url 기준의 절대 URL로, 날짜는 HTML 목록과 같은 형식으로 바꿉니다.
+func parseJSONPage(data []byte, listKey string, base *neturl.URL, keepEmpty bool, now time.Time) ([]pageInformation, []parseWarning, error) {
+	list := json.RawMessage(data)
+	if listKey != "" {
+		for _, key := range strings.Split(listKey, ".") {
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(list, &object); err != nil {
+				return nil, nil, &jsonListingError{url: base.String(), reason: fmt.Sprintf("expected an object holding %q: %v", key, err)}
+			}
+			value, exists := object[key]
+			if !exists {
+				return nil, nil, &jsonListingError{url: base.String(), reason: fmt.Sprintf("response has no %q key (set -json-list-key)", listKey)}
+			}
+			list = value
+		}
+	}
+
+	var decoded []pageInformation
+	if err := json.Unmarshal(list, &decoded); err != nil {
+		return nil, nil, &jsonListingError{url: base.String(), reason: fmt.Sprintf("expected an array of posts: %v", err)}
+	}
+
+	pages := []pageInformation{}
+	warnings := []parseWarning{}
+	for i, page := range decoded {
+		if !keepEmpty && (page.title == "" || page.link == "") {
+			warnings = append(warnings, parseWarning{row: i, reason: "post has no title or link, skipped"})
+			continue
+		}
+		page.link = resolveURL(base, page.link)
+		if page.thumbnail != "" {
+			page.thumbnail = resolveURL(base, page.thumbnail)
+		}
+		if page.category == "" {
+			page.category, _ = splitCategory(page.title)
+		}
+		if page.date != "" {
+			if parsed, ok := parseDate(page.date, now); ok {
+				page.date = parsed
+			} else {
+				warnings = append(warnings, parseWarning{row: i, reason: fmt.Sprintf("unrecognized date %q, kept as is", page.date)})
+			}
+		}
+		page.row = i
+		pages = append(pages, page)
+	}
+	return pages, warnings, nil
+}
+
+// JSON endpoint의 응답이 게시글 목록 형태가 아닐 때의 에러. 다시 요청해도 같을 가능성이 높아서 재시도하지 않습니다.
+type jsonListingError struct {
+	url    string
+	reason string
+}
+
+func (e *jsonListingError) Error() string {
+	return fmt.Sprintf("%s: not a JSON listing: %s", e.url, e.reason)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 초당 요청 수를 제한하는 limiter입니다. 요청 사이의 간격을 1/rate 초로 맞춥니다.
+// rate가 0 이하면 제한하지 않습니다.
+type rateLimiter struct {
+	mu   sync.Mutex
+	rate float64
+	next time.Time
+}
+
+func (l *rateLimiter) SetRate(rate float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = rate
+}
+
+func (l *rateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// 다음 요청을 보내도 되는 시점까지 기다립니다.
+// 기다리는 도중에 ctx가 취소되면 바로 ctx의 에러를 리턴합니다. 그 사이 다른 요청이 자리를 잡지 않았다면
+// 이번에 잡아둔 자리도 돌려놓아서 다음 요청이 불필요하게 기다리지 않게 합니다.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	reserved := at.Add(time.Duration(float64(time.Second) / l.rate))
+	l.next = reserved
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if l.next.Equal(reserved) {
+			l.next = at
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -1,28 +1,55 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
-	"net/http"
+	neturl "net/url"
 	"os"
-	"sort"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/term"
 )
 
 type pageInformation struct {
-	pageNum int
-	title   string
-	user    string
-	view    int
-	link    string
+	pageNum   int
+	numRaw    string // 번호 칸이 숫자가 아닐 때("공지") 그 칸의 text. pageNum은 0
+	title     string
+	user      string
+	view      int
+	link      string
+	thumbnail string // 이미지 글의 썸네일 절대 URL (글만 있는 게시글은 빈 값)
+	imageFile string // -download-images로 내려받은 썸네일의 로컬 경로
+	category  string // 말머리 ([질문] -> 질문), 없으면 빈 값
+	deleted   bool   // 삭제되었지만 목록에 남아있는 게시글
+	dupGroup  int    // 제목이 중복된 게시글 그룹 번호 (0이면 중복 없음)
+	comments  int    // 댓글 수 (제목 옆의 [12])
+	recommend int    // 추천 수
+	isNew     bool   // -seen-db에 없던 게시글
+	date      string // 등록일 ("2024-05-01" 또는 RFC3339). 알아볼 수 없는 형식이면 게시판에 나온 그대로
+	source    string // 수집한 목록 (-mode: normal, recommended)
+	bodyLen   int    // -include-body-length로 센 본문 글자 수 (가져오지 못했으면 -1)
+	newBadge  bool   // 목록에 새 글 아이콘이 붙은 게시글 (-include-badges)
+	hot       bool   // 인기 글 아이콘이 붙은 게시글
+	hasImage  bool   // 이미지 아이콘이나 썸네일이 붙은 게시글
+
+	extra map[string]string // -extra-field로 수집한 필드
+
+	listPage int // 이 게시글을 발견한 목록 page 번호
+	row      int // 목록 page 안에서의 순서 (0부터)
 }
 
-var baseURL string = "https://www.inven.co.kr/board/ff14/4337?p="
-
 func checkErr(err error) {
 	if err != nil {
 		fmt.Println(err.Error())
@@ -30,192 +57,812 @@ func checkErr(err error) {
 	}
 }
 
-func checkCode(res *http.Response) {
-	if res.StatusCode != 200 {
-		log.Fatalln("Request failed with Status:", res.StatusCode)
-	}
-}
-
-func checkPageAvailable(url string, retry int) bool {
-	res, err := http.Get(url)
-
-	if err != nil {
-		if retry > 0 {
-			return checkPageAvailable(url, retry-1)
-		} else {
-			return false
-		}
+// url의 목록 page에 게시글이 있는지 확인합니다. 요청이 실패한 page는 게시글이 없는 것으로 보지만,
+// 차단된 page는 계속 확인해도 같으므로 blockedError를 리턴해서 마지막 page 찾기를 중단합니다.
+func (s *Scraper) checkPageAvailable(ctx context.Context, url string) (bool, error) {
+	var doc *goquery.Document
+	err := s.withRetries(ctx, func() error {
+		var err error
+		doc, err = s.fetch(ctx, url)
+		return err
+	})
+	if isBlocked(err) {
+		return false, err
 	}
-
-	defer res.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(res.Body)
 	if err != nil {
-		if retry > 0 {
-			return checkPageAvailable(url, retry-1)
-		} else {
-			return false
-		}
+		return false, nil
 	}
 
 	if doc.Find("div.board-list table tbody tr td div.no-result").Length() != 0 {
-		return false
+		return false, nil
 	}
 
-	return true
+	return true, nil
 }
 
-func getPages() int {
-	res, err := http.Get(baseURL)
-
-	checkErr(err)
-	checkCode(res)
-
-	defer res.Body.Close()
+// 게시판의 마지막 page 번호를 찾습니다. 첫 page에 게시글이 없으면 ErrNoPages를 리턴합니다.
+func (s *Scraper) getPages(ctx context.Context) (int, error) {
+	if s.mode == "recommended" {
+		return s.probeLastPage(ctx)
+	}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
-	checkErr(err)
+	doc, err := s.fetch(ctx, s.baseURL)
+	if err != nil {
+		return 0, err
+	}
 
 	numList := doc.Find("tbody tr.lgtm td.num span")
 	if numList.Length() == 0 {
-		log.Fatalln("No pages found")
+		return 0, fmt.Errorf("%w: %s has no posts", ErrNoPages, s.baseURL)
 	}
 
 	maxNum := numList.First().Text()
 
+	// page당 게시글 수는 게시판이나 목록 설정에 따라 다르므로, 첫 page의 공지가 아닌 게시글 수를 셉니다.
+	// 게시판 전체가 한 page뿐이라면 적게 세어지지만, 그때는 짐작한 page가 비어 있어서 아래의 이분 탐색으로 찾습니다.
+	s.detectedPerPage = numList.Length()
+
 	// convert string to int
-	maxNumInt, err := strconv.Atoi(maxNum)
-	maxNumInt = maxNumInt/30 + 1 // page당 30개의 게시글이 있음
-	checkErr(err)
+	maxNumInt, err := parsePostNumber(maxNum)
+	if err != nil {
+		return 0, err
+	}
+	maxNumInt = maxNumInt/s.postsPerPage() + 1
+	if s.verbose {
+		log.Printf("%d posts per page, estimating the last page as %d\n", s.postsPerPage(), maxNumInt)
+	}
 
-	for i := maxNumInt; i > 0; i-- {
-		// 페이지 별 게시글이 존재하는지 확인
-		// 게시글이 삭제된 경우, num은 해당 번호를 건너뛰기 때문에 마지막 page는 존재하지 않을 수 있음
-		// 게시글의 num은 1씩 증가하고, 중복되지 않으므로 마지막 page 뒤의 게시글은 존재할 수 없음
-		// 따라서 마지막 Page부터 게시글이 존재하는지 확인하고, 최초로 게시글이 존재하는 page를 리턴합니다.
-		if checkPageAvailable(baseURL+fmt.Sprintf("%v", i), 20) { // 해당 페이지에 게시글이 존재하는지 확인
-			return i // 게시글이 존재한다면 page num을 리턴합니다.
-		} else {
-			continue // 아니라면 반복
+	// 게시글이 삭제된 경우, num은 해당 번호를 건너뛰기 때문에 짐작한 마지막 page는 존재하지 않을 수 있음
+	// 반대로 page마다 게시글이 30개가 아니면 짐작보다 page가 더 많을 수 있으므로, 바로 다음 page부터 확인합니다.
+	if ok, err := s.checkPageAvailable(ctx, s.PageURL(maxNumInt+1)); err != nil {
+		return 0, err
+	} else if ok {
+		if s.verbose {
+			log.Printf("page %d after the estimated last page %d still has posts, searching further\n", maxNumInt+1, maxNumInt)
 		}
+		return s.widenLastPage(ctx, maxNumInt+1)
 	}
-
-	return 0
+	if ok, err := s.checkPageAvailable(ctx, s.PageURL(maxNumInt)); err != nil {
+		return 0, err
+	} else if ok {
+		return maxNumInt, nil
+	}
+	// 게시글의 num은 1씩 증가하고, 중복되지 않으므로 마지막 page 뒤의 게시글은 존재할 수 없음
+	// 따라서 게시글이 있는 page와 없는 page의 경계를 이분 탐색으로 찾습니다.
+	return s.lastPageBetween(ctx, 0, maxNumInt)
 }
 
-func getPageTitle(url string, retry int) ([]pageInformation, error) {
-	fmt.Println("Requesting from : ", url)
-	res, err := http.Get(url)
-
+// retry면 요청이 실패했을 때 RetryPolicy에 따라 다시 요청합니다.
+func (s *Scraper) getPageTitle(ctx context.Context, url string, retry bool) ([]pageInformation, []parseWarning, error) {
+	doc, err := s.fetchListing(ctx, url, retry)
 	if err != nil {
-		if retry > 0 {
-			return getPageTitle(url, retry-1)
-		}
-
-		return nil, err
+		return nil, nil, err
 	}
+	pages, warnings := s.parseListing(doc, url, s.referenceTime())
+	return pages, warnings, nil
+}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
-	if err != nil {
-		res.Body.Close()
-		if retry > 0 {
-			return getPageTitle(url, retry-1)
-		}
-		return nil, err
+// 목록 page url의 문서를 받습니다. retry는 getPageTitle과 같습니다.
+func (s *Scraper) fetchListing(ctx context.Context, url string, retry bool) (*goquery.Document, error) {
+	var doc *goquery.Document
+	fetch := func() error {
+		fmt.Println("Requesting from : ", url)
+		var err error
+		doc, err = s.fetch(ctx, url)
+		return err
+	}
+	var err error
+	if retry {
+		err = s.withRetries(ctx, fetch)
+	} else {
+		err = fetch()
 	}
+	return doc, err
+}
 
-	numList := doc.Find("div.board-list table tbody tr").Clone()
+// url에서 받은 목록 page 문서를 게시글로 바꿉니다. "5분 전" 같은 날짜는 now 기준으로 읽습니다.
+func (s *Scraper) parseListing(doc *goquery.Document, url string, now time.Time) ([]pageInformation, []parseWarning) {
+	base, _ := neturl.Parse(url)
+	pages, warnings := s.parse(doc, base, s.keepEmpty, now)
+	collectExtraFields(doc, pages, s.extraFields)
+	collectBadges(doc, pages, s.badges)
+	return pages, warnings
+}
 
-	res.Body.Close()
+// 목록 page 하나에 나오는 게시글 수 (공지 제외). 첫 page에서 셀 수 없을 때 사용합니다.
+const defaultPostsPerPage = 30
+
+// 목록 page의 게시글 행(tr)
+const listingRowSelector = "div.board-list table tbody tr"
+
+// 게시글 행 안에서 필드를 찾는 selector. parsePage와 -explain이 같이 사용합니다.
+const (
+	titleSelector     = "td.tit div div a" // 제목 text와 링크(href)
+	categorySelector  = "td.tit span.category"
+	numSelector       = "td.num span"
+	userSelector      = "td.user span"
+	viewSelector      = "td.view"
+	commentsSelector  = "td.tit span.con-comment"
+	recommendSelector = "td.reco"
+	thumbnailSelector = "td.tit img"
+	dateSelector      = "td.date"
+	noResultSelector  = "div.no-result" // 게시글이 없을 때 나오는 안내 행
+)
+
+// 목록 page의 게시글 행(tr)들을 pageInformation으로 변환합니다.
+// 썸네일처럼 상대 경로로 나오는 URL은 base를 기준으로 절대 URL로 바꿉니다.
+// 제목 링크가 없거나 링크에 href가 없는 행(광고, 다른 layout의 행)은 keepEmpty가 아니면 건너뛰고 경고로 돌려줍니다.
+// 게시글이 없을 때 나오는 no-result 안내 행은 경고 없이 건너뜁니다.
+// "5분 전" 같은 상대 시간은 now 기준으로 바꿉니다.
+func parsePage(doc *goquery.Document, base *neturl.URL, keepEmpty bool, now time.Time) ([]pageInformation, []parseWarning) {
+	numList := doc.Find(listingRowSelector)
 
 	pages := []pageInformation{}
+	warnings := []parseWarning{}
 
 	numList.Each(func(i int, s *goquery.Selection) {
+		if !keepEmpty && s.Find(titleSelector).Length() == 0 {
+			if s.Find(noResultSelector).Length() == 0 {
+				warnings = append(warnings, parseWarning{row: i, reason: "row has no title link, skipped"})
+			}
+			return
+		}
 
-		title := strings.TrimSpace(s.Find("td.tit div div a").Clone().Children().Remove().End().Text())
+		title := strings.TrimSpace(ownText(s.Find(titleSelector)))
 
-		link, exists := s.Find("td.tit div div a").Attr("href")
-		if !exists {
-			/* handle error */
+		// 말머리는 span.category로 따로 나오거나, 제목 앞에 [말머리] 형태로 붙어서 나옵니다.
+		category := cleanCategory(s.Find(categorySelector).First().Text())
+		if category == "" {
+			category, _ = splitCategory(title)
 		}
 
-		pageNum, err := strconv.Atoi(s.Find("td.num span").Text())
+		link, exists := s.Find(titleSelector).Attr("href")
+		if !exists && !keepEmpty {
+			warnings = append(warnings, parseWarning{row: i, reason: "title link has no href, skipped"})
+			return
+		}
+
+		numText := strings.TrimSpace(s.Find(numSelector).Text())
+		numRaw := ""
+		pageNum, err := parsePostNumber(numText)
 		if err != nil {
-			/* handle error */
+			numRaw = numText
 		}
 
-		user := s.Find("td.user span").Text()
+		user := s.Find(userSelector).Text()
 
-		view, err := strconv.Atoi(strings.Replace(s.Find("td.view").Text(), ",", "", -1))
-		if err != nil {
-			/* handle error */
+		// 숫자로 읽을 수 없는 조회수, 댓글 수, 추천 수는 0으로 두고 경고로 돌려줍니다.
+		count := func(name, selector string) int {
+			n, err := parseCount(s.Find(selector).First().Text())
+			if err != nil {
+				warnings = append(warnings, parseWarning{row: i, reason: fmt.Sprintf("%s: %v, read as 0", name, err)})
+			}
+			return n
+		}
+		view := count("view", viewSelector)
+		comments := count("comments", commentsSelector)
+		recommend := count("recommend", recommendSelector)
+
+		thumbnail := ""
+		img := s.Find(thumbnailSelector).First()
+		if src, exists := img.Attr("data-src"); exists && src != "" {
+			thumbnail = resolveURL(base, src)
+		} else if src, exists := img.Attr("src"); exists && src != "" {
+			thumbnail = resolveURL(base, src)
+		}
+
+		deleted := isDeletedRow(s, title)
+
+		date := strings.TrimSpace(s.Find(dateSelector).Text())
+		if date != "" {
+			if parsed, ok := parseDate(date, now); ok {
+				date = parsed
+			} else {
+				warnings = append(warnings, parseWarning{row: i, reason: fmt.Sprintf("unrecognized date %q, kept as is", date)})
+			}
 		}
 
 		pageInfo := &pageInformation{
-			pageNum: pageNum,
-			title:   title,
-			user:    user,
-			view:    view,
-			link:    link,
+			pageNum:   pageNum,
+			numRaw:    numRaw,
+			title:     title,
+			user:      user,
+			view:      view,
+			link:      link,
+			thumbnail: thumbnail,
+			category:  category,
+			deleted:   deleted,
+			comments:  comments,
+			recommend: recommend,
+			date:      date,
+			row:       i,
 		}
 
 		pages = append(pages, *pageInfo)
 	})
 
-	return pages, nil
+	return pages, warnings
 }
 
-func goroutineMethod(pageNum int, c chan<- []pageInformation) {
-	pages, err := getPageTitle(baseURL+fmt.Sprintf("%v", pageNum), 20)
+// 선택된 요소들의 직속 text node만 이어붙여서 리턴합니다.
+// 제목 a 태그 안의 카테고리, 댓글 수 같은 자식 요소의 text는 제외됩니다.
+// Clone().Children().Remove().End().Text()와 결과는 같지만 subtree를 복사하지 않습니다.
+func ownText(sel *goquery.Selection) string {
+	var b strings.Builder
+	for _, n := range sel.Nodes {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				b.WriteString(c.Data)
+			}
+		}
+	}
+	return b.String()
+}
+
+// page 하나를 수집한 결과. 실패했다면 err가 채워집니다.
+type pageResult struct {
+	pageNum  int
+	pages    []pageInformation
+	warnings []parseWarning
+	dropped  int // -require-fields로 뺀 행 수
+	err      error
+}
+
+// 목록 page 하나를 수집합니다.
+func (s *Scraper) scrapePage(ctx context.Context, pageNum int) (result pageResult) {
+	// 이상한 행 하나 때문에 파싱 중에 panic이 나도 전체 실행이 죽지 않도록, 그 page만 실패로 돌려줍니다.
+	defer func() {
+		if r := recover(); r != nil {
+			err := &panicError{value: r}
+			log.Printf("page %d: %v\n", pageNum, err)
+			if s.verbose {
+				log.Printf("page %d: %s", pageNum, debug.Stack())
+			}
+			result = pageResult{pageNum: pageNum, err: err}
+		}
+	}()
+
+	// 이미 취소된 run이라면 요청하지 않고 바로 실패로 돌려줍니다.
+	if err := ctx.Err(); err != nil {
+		return pageResult{pageNum: pageNum, err: err}
+	}
+
+	// 재시도까지 합쳐서 page 하나에 쓰는 시간을 제한합니다. 전체 run의 ctx와 구분해야 다른 page는 계속 수집합니다.
+	pageCtx := ctx
+	if s.pageTimeout > 0 {
+		var cancel context.CancelFunc
+		pageCtx, cancel = context.WithTimeout(ctx, s.pageTimeout)
+		defer cancel()
+	}
+
+	pages, warnings, err := s.getListingPage(pageCtx, pageNum, true)
+	if err == nil {
+		pages, warnings, err = s.checkMinRows(pageCtx, pageNum, pages, warnings)
+	}
+	if err == nil && s.normalizeUnicode {
+		normalizeUnicode(pages)
+	}
+	dropped := 0
+	if err == nil {
+		pages, warnings, dropped, err = s.checkRequiredFields(pageNum, pages, warnings)
+	}
+	if err != nil && ctx.Err() == nil && errors.Is(pageCtx.Err(), context.DeadlineExceeded) {
+		err = &pageTimeoutError{pageNum: pageNum, timeout: s.pageTimeout}
+	}
 	if err != nil {
-		log.Println(err)
-		c <- nil
-	} else {
-		c <- pages
+		if ctx.Err() == nil {
+			log.Println(err)
+		}
+		return pageResult{pageNum: pageNum, err: err}
 	}
+	for i := range pages {
+		pages[i].listPage = pageNum
+		pages[i].source = s.source()
+	}
+	for i := range warnings {
+		warnings[i].listPage = pageNum
+	}
+	return pageResult{pageNum: pageNum, pages: pages, warnings: warnings, dropped: dropped}
 }
 
-func writePages(pages *[]pageInformation) {
-	file, err := os.Create("pages.csv")
-	checkErr(err)
+// worker에서 난 panic을 page 하나의 에러로 바꾼 것
+type panicError struct {
+	value any
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic while scraping: %v (run with -v to see the stack)", e.value)
+}
+
+// -format에 준 형식마다 결과 파일을 쓰고, 쓴 파일 경로를 리턴합니다. 수집은 한 번만 하고 같은 결과를 형식별로 씁니다.
+// -partition-by를 주면 형식마다 partition별 디렉토리(date=2024-05-01/pages.csv)에 나눠서 씁니다.
+// 쓰기에 실패하면 그때까지 쓴 파일 경로와 에러를 리턴합니다.
+func writePages(pages *[]pageInformation, cfg Config) ([]string, error) {
+	written := []string{}
+	write := func(path, format string, pages []pageInformation) error {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := writeOutput(path, format, pages, cfg); err != nil {
+			return err
+		}
+		if cfg.ValidateOutput {
+			if err := validateOutput(path, format, pages, cfg); err != nil {
+				return err
+			}
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	for _, format := range cfg.formats() {
+		path := cfg.outputPath(format)
+		if cfg.PartitionBy == "" {
+			if err := write(path, format, *pages); err != nil {
+				return written, err
+			}
+			continue
+		}
+
+		values, groups := partitionPages(*pages, datePartition)
+		for _, value := range values {
+			if err := write(partitionPath(path, cfg.PartitionBy, value), format, groups[value]); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func writeOutput(path, format string, pages []pageInformation, cfg Config) error {
+	return writeFileAtomic(path, func(file io.Writer) error {
+		if write, exists := columnarWriters[format]; exists {
+			return write(file, pages, cfg)
+		}
+
+		// BOM은 Excel에서 여는 CSV에만 붙입니다.
+		out, err := newOutputWriter(file, cfg.Encoding, cfg.BOM && format == "csv")
+		if err != nil {
+			return err
+		}
 
-	w := csv.NewWriter(file)
-	defer w.Flush()
-	headers := []string{"No.", "Title", "User", "View", "Link"}
+		switch format {
+		case "json":
+			err = writeJSON(out, pages, cfg.Compact)
+		case "ndjson":
+			err = writeNDJSON(out, pages)
+		default:
+			err = writeCSV(out, pages, cfg)
+		}
+		if err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// -strip-newlines: 값 안의 줄바꿈을 공백으로 바꿔서 CSV의 행 하나가 항상 한 줄이 되게 합니다.
+var newlineReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// 게시글 하나를 CSV 행으로 바꿉니다.
+func (c Config) csvRecord(page pageInformation, fields []outputField) []string {
+	record := []string{}
+	for _, f := range fields {
+		value := f.value(page)
+		if c.StripNewlines {
+			value = newlineReplacer.Replace(value)
+		}
+		record = append(record, value)
+	}
+	return record
+}
+
+// CSV 헤더 앞에 붙이는 수집 정보 주석. (-csv-header-comment)
+func writeCSVComment(out io.Writer, rows int, cfg Config) error {
+	newline := "\n"
+	if cfg.CRLF {
+		newline = "\r\n"
+	}
+	lines := []string{
+		"url: " + cfg.BaseURL,
+		"scraped_at: " + cfg.startedAt.Format(time.RFC3339),
+		"version: " + versionString(),
+		"rows: " + strconv.Itoa(rows),
+	}
+	for _, line := range lines {
+		if _, err := io.WriteString(out, "# "+line+newline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	wErr := w.Write(headers)
-	checkErr(wErr)
+func writeCSV(out io.Writer, pages []pageInformation, cfg Config) error {
+	if cfg.CSVHeaderComment {
+		if err := writeCSVComment(out, len(pages), cfg); err != nil {
+			return err
+		}
+	}
+
+	w := csv.NewWriter(out)
+	w.UseCRLF = cfg.CRLF
+	fields := cfg.csvFields()
+
+	headers := []string{}
+	for _, f := range fields {
+		headers = append(headers, cfg.csvHeader(f))
+	}
+	if err := w.Write(headers); err != nil {
+		return err
+	}
 
-	for _, page := range *pages {
-		pageInfo := []string{fmt.Sprintf("%v", page.pageNum), page.title, page.user, fmt.Sprintf("%v", page.view), page.link}
-		wErr := w.Write(pageInfo)
-		checkErr(wErr)
+	for _, page := range pages {
+		if err := w.Write(cfg.csvRecord(page, fields)); err != nil {
+			return err
+		}
 	}
+
+	w.Flush()
+	return w.Error()
 }
 
 // FIX: 왜 goroutine을 사용하면 에러 발생하는가?
 // Response: goroutine 속 map의 원본을 포인터로 전달하여 수정하도록 쓰여진 코드이기 때문에 발생하는 문제같다. 채널을 통해 데이터를 전달받아서 메인 함수에서 취합하니 해결되었다.
 var goroutineOption = true
 
+// 결과가 0건일 때의 exit code. 스크립트에서 "성공했지만 비어 있음"을 구분할 수 있게 합니다.
+const exitNoResults = 3
+
+// 게시판이 로그인/captcha/차단 page를 돌려줘서 중단했을 때의 exit code
+const exitBlocked = 4
+
+// 실패한 page가 -max-failures-abort를 넘어서 중단했을 때의 exit code
+const exitTooManyFailures = 5
+
+// -timeout이 지나서 중단했을 때의 exit code
+const exitTimeout = 6
+
+// Ctrl-C로 중단되었을 때의 exit code (128 + SIGINT)
+const exitInterrupted = 130
+
 func main() {
-	results := []pageInformation{}
-	maxPageNum := getPages() // 최대 page를 계산해서 받아오는 부분
-	fmt.Println(fmt.Sprint(maxPageNum) + "pages found")
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		if err := runMerge(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	cfg := defaultConfig()
+	if path := configPathFromArgs(os.Args[1:]); path != "" {
+		checkErr(cfg.loadFile(path))
+	}
+	if err := cfg.loadEnv(os.LookupEnv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cfg.registerFlags(flag.CommandLine)
+	flag.Parse()
 
-	c := make(chan []pageInformation)
+	if cfg.PrintVersion {
+		fmt.Println(versionString())
+		return
+	}
 
-	for i := 1; i <= maxPageNum; i++ {
-		go goroutineMethod(i, c)
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
 
-	for i := 1; i <= maxPageNum; i++ {
-		pages := <-c
-		results = append(results, pages...)
+	if len(cfg.CompareUsers) > 0 {
+		checkErr(runCompareUsers(cfg))
+		return
 	}
 
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].pageNum < results[j].pageNum
-	})
+	boards := []string{cfg.BaseURL}
+	if cfg.BaseURL == "-" {
+		var err error
+		boards, err = readBoardURLs(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		// proxy 확인, manifest처럼 URL 하나를 쓰는 곳에서는 첫 게시판을 씁니다.
+		cfg.BaseURL = boards[0]
+	}
+
+	log.Println(versionString())
+	startedAt := time.Now()
+	cfg.startedAt = startedAt
+
+	stopProfiling, err := startProfiling(cfg)
+	checkErr(err)
+	defer stopProfiling()
+
+	status := &healthState{}
+	if cfg.HealthAddr != "" {
+		checkErr(startHealthServer(cfg, status))
+	}
 
-	writePages(&results)
+	opts := cfg.scraperOptions()
+	if cfg.Audit != "" {
+		audit, err := openAuditLog(cfg.Audit)
+		checkErr(err)
+		opts = append(opts, WithAuditLog(audit))
+	}
+	if cfg.Sink != "" {
+		sink, err := openSink(cfg.Sink, cfg.sinkFlush())
+		checkErr(err)
+		opts = append(opts, WithSink(sink))
+	}
+
+	if len(cfg.Proxies) > 0 {
+		health := checkProxies(context.Background(), cfg.proxyURLs(), cfg.BaseURL)
+		if cfg.ProxyTest {
+			for _, h := range health {
+				fmt.Println(h)
+			}
+			return
+		}
+
+		proxies := []*neturl.URL{}
+		for _, h := range health {
+			if h.err != nil {
+				log.Println("Skipping proxy", h)
+				continue
+			}
+			proxies = append(proxies, h.proxy)
+		}
+		if len(proxies) == 0 {
+			log.Fatalln("No working proxy: all", len(health), "proxies failed the health check")
+		}
+		opts = append(opts, WithProxies(proxies))
+	}
+
+	var seen *seenSet
+	if cfg.SeenDB != "" {
+		if cfg.SeenReset {
+			seen = &seenSet{path: cfg.SeenDB}
+		} else {
+			var err error
+			seen, err = loadSeenSet(cfg.SeenDB)
+			checkErr(err)
+		}
+		opts = append(opts, WithPostProcessor(seenProcessor(seen)))
+	}
+
+	watermark := 0
+	if cfg.Watermark != "" {
+		var err error
+		watermark, err = readWatermark(cfg.Watermark)
+		checkErr(err)
+		opts = append(opts, WithPostProcessor(watermarkFilter(&watermark)))
+	}
+
+	// 자동화된 실행(cron, pipe)에서는 물어볼 사람이 없으므로 터미널에서 실행할 때만 묻습니다.
+	if !cfg.Yes && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
+		opts = append(opts, WithConfirm(cfg.ConfirmPages, func(pages int) bool {
+			return promptLargeScrape(os.Stdin, os.Stdout, pages, cfg.RPS)
+		}))
+	}
+
+	scraper := NewScraper(opts...)
+	defer scraper.Close()
+	// os.Exit는 defer를 실행하지 않으므로 종료 코드를 정할 때는 직접 Close합니다.
+	exit := func(code int) {
+		scraper.Close()
+		stopProfiling()
+		os.Exit(code)
+	}
+	if cfg.Verbose {
+		log.Printf("random seed %d (pass -seed %d to reproduce this run)\n", scraper.Seed(), scraper.Seed())
+	}
+
+	if cfg.PrintURLTemplate {
+		printURLTemplate(context.Background(), scraper, cfg)
+		return
+	}
+
+	if cfg.Explain > 0 {
+		checkErr(scraper.explain(context.Background(), os.Stdout, cfg.Explain))
+		return
+	}
+
+	if cfg.CheckOnly {
+		checkErr(scraper.Preflight(context.Background()))
+		fmt.Println(cfg.BaseURL + " is reachable and returns HTML")
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// -interval이면 중단할 때까지 수집과 결과 쓰기를 반복합니다. 매번 같은 출력 파일을 새로 씁니다.
+	for {
+		// 게시판은 하나씩 차례로 수집하므로, 게시판이 여러 개여도 동시 요청 수는 -workers, -rps를 넘지 않습니다.
+		results := []pageInformation{}
+		outputs := []string{}
+		collected := 0
+		failedPages := 0
+		interrupted := false
+		aborted := false
+		timedOut := false
+		skipped := false // -interval에서 실패한 실행은 결과를 쓰지 않고 다음 실행을 기다립니다.
+		var runErr error // /lastrun에 기록할 이번 실행의 에러
+		runCtx, cancelRun := ctx, context.CancelFunc(func() {})
+		if cfg.Timeout > 0 {
+			runCtx, cancelRun = context.WithTimeout(ctx, time.Duration(cfg.Timeout))
+		}
+		for i, board := range boards {
+			if len(boards) > 1 {
+				WithBaseURL(board)(scraper)
+				WithSnapshotDir(cfg.boardConfig(board).SnapshotHTML)(scraper)
+				log.Printf("Board %d of %d: %s\n", i+1, len(boards), board)
+			}
+
+			boardResults, err := scraper.Scrape(runCtx)
+			interrupted = errors.Is(err, context.Canceled) && ctx.Err() != nil
+			aborted = isTooManyFailures(err)
+			timedOut = errors.Is(err, ErrTimeout) && runCtx.Err() != nil
+			if errors.Is(err, ErrBlocked) && cfg.Interval == 0 {
+				log.Println(err)
+				exit(exitBlocked)
+			}
+			if errors.Is(err, errNotConfirmed) {
+				log.Println(err)
+				exit(1)
+			}
+			// 게시글이 없는 게시판은 결과가 0건인 실행과 같이 처리합니다. (exit code 3)
+			if errors.Is(err, ErrNoPages) {
+				log.Println(err)
+				err = nil
+			}
+			if err != nil {
+				runErr = err
+			}
+			if err != nil && !interrupted && !aborted && !timedOut {
+				// 첫 page의 5xx, DNS 에러, 차단처럼 한 번의 실패 때문에 -interval 반복을 멈추지 않습니다.
+				if cfg.Interval == 0 {
+					log.Println(err)
+					exit(1)
+				}
+				log.Printf("Run failed, skipping its output until the next run: %v\n", err)
+				skipped = true
+				break
+			}
+			if interrupted {
+				log.Println("Interrupted, writing partial results")
+			}
+			if aborted {
+				log.Printf("%v, writing partial results\n", err)
+			}
+			if timedOut {
+				log.Printf("Stopped after -timeout %v, writing partial results\n", time.Duration(cfg.Timeout))
+			}
+			if cfg.Stats {
+				fmt.Println(scraper.Stats())
+			}
+			logParseWarnings(scraper.Warnings())
+			collected += scraper.Collected()
+			failedPages += len(scraper.Failed())
+
+			if cfg.PerBoard {
+				written, err := writePages(&boardResults, cfg.boardConfig(board))
+				outputs = append(outputs, written...)
+				if err != nil {
+					log.Printf("Writing results failed: %v\n", err)
+					runErr = err
+					skipped = true
+					break
+				}
+			}
+			results = append(results, boardResults...)
+			if interrupted || aborted || timedOut {
+				break
+			}
+		}
+		cancelRun()
+		writeRun := func() error {
+			if !cfg.PerBoard {
+				written, err := writePages(&results, cfg)
+				outputs = written
+				if err != nil {
+					return err
+				}
+			}
+			if cfg.PrettyTable {
+				printTable(results, cfg)
+			}
+			if cfg.ReportGaps {
+				if err := reportGaps(os.Stdout, results, cfg, scraper.Failed()); err != nil {
+					return err
+				}
+			}
+
+			// 결과 파일을 쓴 뒤에 기록해야, 쓰기에 실패했을 때 새 게시글을 본 것으로 잃어버리지 않습니다.
+			if seen != nil {
+				seen.add(results)
+				if err := seen.save(); err != nil {
+					return err
+				}
+			}
+			if cfg.Watermark != "" {
+				watermark = advanceWatermark(watermark, results)
+				if err := writeWatermark(cfg.Watermark, watermark); err != nil {
+					return err
+				}
+			}
+
+			if cfg.Manifest != "" {
+				return writeManifest(cfg.Manifest, manifest{
+					BaseURL:   cfg.BaseURL,
+					Boards:    boardList(boards),
+					Output:    cfg.outputPath(cfg.formats()[0]),
+					Outputs:   outputs,
+					Rows:      len(results),
+					Schema:    cfg.manifestSchema(),
+					StartedAt: startedAt,
+					EndedAt:   time.Now(),
+				})
+			}
+			return nil
+		}
+		if !skipped {
+			// 결과 파일이나 seen, watermark, manifest를 쓰지 못해도 -interval 반복은 멈추지 않고 다음 실행을 기다립니다.
+			if err := writeRun(); err != nil {
+				log.Printf("Writing results failed: %v\n", err)
+				runErr = err
+				skipped = true
+			}
+		}
+		run := lastRun{StartedAt: startedAt, EndedAt: time.Now(), FailedPages: failedPages}
+		if !skipped {
+			run.Rows = len(results)
+		}
+		if runErr != nil {
+			run.Error = runErr.Error()
+		}
+		status.record(run)
+
+		// 수집에 실패한 실행은 위에서 이미 종료했으므로, 여기서는 결과를 쓰지 못한 실행입니다.
+		if runErr != nil && skipped && cfg.Interval == 0 {
+			exit(1)
+		}
+
+		// 중단하면 -interval이어도 종료합니다. 시간 초과나 실패한 page가 너무 많은 실행은 -interval이면 다음 실행을 기다립니다.
+		if interrupted {
+			exit(exitInterrupted)
+		}
+		if aborted && cfg.Interval == 0 {
+			exit(exitTooManyFailures)
+		}
+		if timedOut && cfg.Interval == 0 {
+			exit(exitTimeout)
+		}
+
+		if len(results) == 0 && !skipped {
+			if collected == 0 {
+				log.Println("No rows written: the board has no posts in the requested pages")
+			} else {
+				log.Printf("No rows written: all %d collected posts were filtered out\n", collected)
+			}
+			// -interval로 반복할 때는 새 글이 없는 실행이 보통이므로 종료하지 않습니다.
+			if !cfg.QuietOnEmpty && cfg.Interval == 0 {
+				exit(exitNoResults)
+			}
+		}
+
+		if cfg.Interval == 0 {
+			return
+		}
+		wait := scraper.nextInterval(time.Duration(cfg.Interval))
+		log.Printf("Next run in %v\n", wait.Round(time.Second))
+		if !scraper.idle(ctx, wait, boards) {
+			return
+		}
+		startedAt = time.Now()
+		cfg.startedAt = startedAt
+	}
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// 실행 정보를 기록하는 manifest 파일의 내용입니다.
+type manifest struct {
+	Version   string         `json:"version"`
+	Commit    string         `json:"commit"`
+	BuildDate string         `json:"build_date"`
+	BaseURL   string         `json:"base_url"`
+	Boards    []string       `json:"boards,omitempty"` // -url -로 여러 게시판을 수집했을 때 수집한 순서대로
+	Output    string         `json:"output"`
+	Outputs   []string       `json:"outputs"` // 실제로 쓴 파일. -format에 여러 형식을 주면 형식마다, -partition-by면 partition마다 하나씩
+	Rows      int            `json:"rows"`
+	Schema    []schemaColumn `json:"schema,omitempty"` // columnar 형식(-format parquet)의 컬럼 타입
+	StartedAt time.Time      `json:"started_at"`
+	EndedAt   time.Time      `json:"ended_at"`
+}
+
+func writeManifest(path string, m manifest) error {
+	m.Version = version
+	m.Commit = commit
+	m.BuildDate = buildDate
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(append(data, '\n'))
+		return err
+	})
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// merge에서 같은 게시글이 여러 파일에 있을 때 남길 행을 고르는 방법
+var mergeKeepModes = []string{"views", "latest"}
+
+// 같은 게시글의 행 중 어느 것을 남길지 정합니다. views는 조회수가 더 큰 행, latest는 나중에 준 파일의 행을 남깁니다.
+// 같은 게시글인지는 fields(-dedup-key)로 구분합니다. (dedupKeyOf)
+func mergeExports(exports [][]pageInformation, keep string, fields []outputField) []pageInformation {
+	index := map[string]int{}
+	merged := []pageInformation{}
+	for _, pages := range exports {
+		for _, page := range pages {
+			key := dedupKeyOf(page, fields)
+			i, exists := index[key]
+			if !exists {
+				index[key] = len(merged)
+				merged = append(merged, page)
+				continue
+			}
+			if keep == "latest" || page.view > merged[i].view {
+				merged[i] = page
+			}
+		}
+	}
+	sortPages(merged)
+	return merged
+}
+
+// example-webscraper merge [옵션] 파일...
+// 이전에 쓴 CSV, JSON, NDJSON 결과 파일들을 읽어서 게시글 번호(-dedup-key)로 중복을 제거하고, 번호 순서로 정렬해서 한 파일로 씁니다.
+// 출력 옵션(-o, -format, -fields, -encoding 등)은 수집할 때와 같습니다. 요청은 보내지 않습니다.
+func runMerge(args []string) error {
+	cfg := defaultConfig()
+	if path := configPathFromArgs(args); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return err
+		}
+	}
+	if err := cfg.loadEnv(os.LookupEnv); err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	cfg.registerFlags(fs)
+	keep := fs.String("keep", "views", "which row to keep when a post is in several files: "+strings.Join(mergeKeepModes, ", ")+" (views keeps the highest view count, latest the file given last)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: example-webscraper merge [options] file...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	validKeep := false
+	for _, mode := range mergeKeepModes {
+		validKeep = validKeep || mode == *keep
+	}
+	if !validKeep {
+		return fmt.Errorf("-keep %q is not supported (expected %s)", *keep, strings.Join(mergeKeepModes, ", "))
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// 입력 파일이 모두 게시글 번호 순서라면 파일 크기와 상관없이 적은 메모리로 합칩니다.
+	if cfg.streamableMerge() {
+		read, written, path, err := streamMerge(fs.Args(), *keep, cfg)
+		if err == nil {
+			log.Printf("merged %d rows from %d files into %d posts: %s\n", read, fs.NArg(), written, path)
+			return nil
+		}
+		var unsorted *unsortedExportError
+		if !errors.As(err, &unsorted) {
+			return err
+		}
+		log.Printf("%v, merging in memory instead\n", err)
+	}
+
+	exports := [][]pageInformation{}
+	total := 0
+	for _, path := range fs.Args() {
+		pages, err := readExport(path, cfg.Encoding)
+		if err != nil {
+			return err
+		}
+		exports = append(exports, pages)
+		total += len(pages)
+	}
+
+	merged := mergeExports(exports, *keep, cfg.dedupFields())
+	outputs, err := writePages(&merged, cfg)
+	if err != nil {
+		return err
+	}
+	log.Printf("merged %d rows from %d files into %d posts: %v\n", total, len(exports), len(merged), outputs)
+	return nil
+}
+
+// 입력 파일이 게시글 번호 순서가 아니어서 streaming merge를 할 수 없을 때의 에러
+type unsortedExportError struct {
+	path string
+}
+
+func (e *unsortedExportError) Error() string {
+	return fmt.Sprintf("%s is not sorted by post number", e.path)
+}
+
+// 파일 하나에서 다음에 merge할 게시글
+type mergeHead struct {
+	stream *exportStream
+	page   pageInformation
+	index  int // 명령행에서 준 파일 순서
+	last   int // 지금까지 읽은 가장 큰 게시글 번호
+}
+
+// 게시글 번호, 파일 순서로 정렬되는 heap
+type mergeHeap []*mergeHead
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if h[i].page.pageNum != h[j].page.pageNum {
+		return h[i].page.pageNum < h[j].page.pageNum
+	}
+	return h[i].index < h[j].index
+}
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(*mergeHead)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	head := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return head
+}
+
+// head의 다음 게시글을 읽습니다. 파일이 끝나면 false를 리턴합니다.
+func (h *mergeHead) advance() (bool, error) {
+	page, err := h.stream.Next()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if page.pageNum < h.last {
+		return false, &unsortedExportError{path: h.stream.path}
+	}
+	h.page, h.last = page, page.pageNum
+	return true, nil
+}
+
+// 게시글 번호 순서로 정렬된 파일들을 k-way merge로 합쳐서 게시글 하나씩 emit에 넘깁니다.
+// 파일마다 게시글 하나만 메모리에 두고, 번호가 없는 공지만 모아 두었다가 맨 앞에 씁니다.
+// 결과는 mergeExports와 같습니다. 정렬되지 않은 파일이 있으면 unsortedExportError를 리턴합니다.
+// 번호가 같은 게시글만 모아서 fields로 구분하므로, fields가 있다면 num이 들어 있어야 합니다. (streamableDedupKey)
+func streamMergeExports(paths []string, keep, encodingName string, fields []outputField, emit func(pageInformation) error) (read, written int, err error) {
+	h := &mergeHeap{}
+	for i, path := range paths {
+		stream, err := openExportStream(path, encodingName)
+		if err != nil {
+			return read, written, err
+		}
+		defer stream.Close()
+
+		head := &mergeHead{stream: stream, index: i}
+		if ok, err := head.advance(); err != nil {
+			return read, written, err
+		} else if ok {
+			heap.Push(h, head)
+		}
+	}
+
+	write := func(page pageInformation) error {
+		written++
+		return emit(page)
+	}
+	notices := []pageInformation{}
+	noticeIndex := map[string]int{}
+	noticesWritten := false
+	writeNotices := func() error {
+		noticesWritten = true
+		sortPages(notices)
+		for _, notice := range notices {
+			if err := write(notice); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// 아직 쓰지 않은, 번호가 같은 게시글들. 같은 게시글끼리는 남길 행 하나만 둡니다.
+	group := []pageInformation{}
+	groupIndex := map[string]int{}
+	writeGroup := func() error {
+		sortPages(group)
+		for _, page := range group {
+			if err := write(page); err != nil {
+				return err
+			}
+		}
+		group = group[:0]
+		clear(groupIndex)
+		return nil
+	}
+
+	for h.Len() > 0 {
+		head := (*h)[0]
+		page := head.page
+		read++
+		if ok, err := head.advance(); err != nil {
+			return read, written, err
+		} else if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+
+		key := dedupKeyOf(page, fields)
+		if page.pageNum == 0 {
+			if i, exists := noticeIndex[key]; !exists {
+				noticeIndex[key] = len(notices)
+				notices = append(notices, page)
+			} else if keep == "latest" || page.view > notices[i].view {
+				notices[i] = page
+			}
+			continue
+		}
+		if !noticesWritten {
+			if err := writeNotices(); err != nil {
+				return read, written, err
+			}
+		}
+
+		if len(group) > 0 && group[0].pageNum != page.pageNum {
+			if err := writeGroup(); err != nil {
+				return read, written, err
+			}
+		}
+		if i, exists := groupIndex[key]; !exists {
+			groupIndex[key] = len(group)
+			group = append(group, page)
+		} else if keep == "latest" || page.view > group[i].view {
+			group[i] = page
+		}
+	}
+
+	if !noticesWritten {
+		if err := writeNotices(); err != nil {
+			return read, written, err
+		}
+	}
+	if err := writeGroup(); err != nil {
+		return read, written, err
+	}
+	return read, written, nil
+}
+
+// 한 형식의 출력 파일에 게시글을 하나씩 씁니다. writeCSV, writeJSON, writeNDJSON과 같은 결과를 만듭니다.
+type pageWriter struct {
+	write  func(pageInformation) error
+	finish func() error
+}
+
+func newPageWriter(out io.Writer, format string, cfg Config) (*pageWriter, error) {
+	switch format {
+	case "json":
+		return newJSONPageWriter(out, cfg.Compact), nil
+	case "ndjson":
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		return &pageWriter{write: func(page pageInformation) error { return enc.Encode(page) }, finish: func() error { return nil }}, nil
+	}
+
+	w := csv.NewWriter(out)
+	w.UseCRLF = cfg.CRLF
+	fields := cfg.csvFields()
+	headers := []string{}
+	for _, f := range fields {
+		headers = append(headers, cfg.csvHeader(f))
+	}
+	if err := w.Write(headers); err != nil {
+		return nil, err
+	}
+	return &pageWriter{
+		write: func(page pageInformation) error { return w.Write(cfg.csvRecord(page, fields)) },
+		finish: func() error {
+			w.Flush()
+			return w.Error()
+		},
+	}, nil
+}
+
+// json.Encoder로 배열 전체를 쓴 것과 같은 모양으로 원소를 하나씩 씁니다.
+func newJSONPageWriter(out io.Writer, compact bool) *pageWriter {
+	open, sep, end, indent := "[", ",", "]\n", ""
+	if !compact {
+		open, sep, end, indent = "[\n  ", ",\n  ", "\n]\n", "  "
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if !compact {
+		enc.SetIndent(indent, indent)
+	}
+
+	count := 0
+	return &pageWriter{
+		write: func(page pageInformation) error {
+			buf.Reset()
+			if err := enc.Encode(page); err != nil {
+				return err
+			}
+			prefix := sep
+			if count == 0 {
+				prefix = open
+			}
+			count++
+			_, err := io.WriteString(out, prefix+strings.TrimSuffix(buf.String(), "\n"))
+			return err
+		},
+		finish: func() error {
+			if count == 0 {
+				_, err := io.WriteString(out, "[]\n")
+				return err
+			}
+			_, err := io.WriteString(out, end)
+			return err
+		},
+	}
+}
+
+// streaming merge로 쓸 수 있는 출력 설정인지 확인합니다. 형식이 여러 개이거나, columnar 형식이거나, 나눠 쓰거나,
+// 행 수를 미리 알아야 하거나(-csv-header-comment), 쓴 행을 다시 비교해야(-validate-output) 하면 메모리에서 합칩니다.
+func (c Config) streamableMerge() bool {
+	return len(c.formats()) == 1 && !isColumnarFormat(c.formats()[0]) && c.PartitionBy == "" && !c.CSVHeaderComment && !c.ValidateOutput &&
+		c.streamableDedupKey()
+}
+
+// -dedup-key에 num이 없으면 같은 게시글이 번호 순서로 이어서 나오지 않으므로 메모리에서 합칩니다.
+func (c Config) streamableDedupKey() bool {
+	names := parseDedupKey(c.DedupKey)
+	for _, name := range names {
+		if name == "num" {
+			return true
+		}
+	}
+	return len(names) == 0
+}
+
+// paths를 streamMergeExports로 합쳐서 출력 파일 하나에 씁니다.
+func streamMerge(paths []string, keep string, cfg Config) (read, written int, path string, err error) {
+	format := cfg.formats()[0]
+	path = cfg.outputPath(format)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, 0, path, err
+	}
+
+	err = writeFileAtomic(path, func(file io.Writer) error {
+		out, err := newOutputWriter(file, cfg.Encoding, cfg.BOM && format == "csv")
+		if err != nil {
+			return err
+		}
+
+		w, err := newPageWriter(out, format, cfg)
+		if err == nil {
+			read, written, err = streamMergeExports(paths, keep, cfg.Encoding, cfg.dedupFields(), w.write)
+		}
+		if err == nil {
+			err = w.finish()
+		}
+		if err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+	return read, written, path, err
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// -min-rows-action retry에서 page를 다시 받아보는 횟수
+const minRowsRetries = 3
+
+var minRowsActions = []string{"warn", "retry", "skip"}
+
+// 마지막 page가 아닌데 파싱된 행이 n개보다 적은 page를 action(warn, retry, skip)으로 처리합니다.
+// 정상적인 목록 page에는 30개 정도의 행이 있으므로, 갑자기 적게 나온다면 markup이 바뀌었거나
+// 속도 제한 때문에 일부만 받은 것일 수 있습니다.
+//   - warn: 경고를 남기고 결과는 그대로 사용합니다.
+//   - retry: 최대 minRowsRetries번 다시 받아보고, 그래도 적으면 경고를 남기고 마지막 결과를 사용합니다.
+//   - skip: 실패한 page로 처리합니다. (-retry-failures로 다시 수집할 수 있음)
+func WithMinRows(n int, action string) Option {
+	return func(s *Scraper) {
+		s.minRows = n
+		s.minRowsAction = action
+	}
+}
+
+// 행이 모자란 page의 경고
+type shortPageError struct {
+	pageNum int
+	rows    int
+	min     int
+}
+
+func (e *shortPageError) Error() string {
+	return fmt.Sprintf("page %d has only %d rows (expected at least %d)", e.pageNum, e.rows, e.min)
+}
+
+// getPageTitle 결과의 행 수를 확인합니다. skip이면 shortPageError를 리턴합니다.
+func (s *Scraper) checkMinRows(ctx context.Context, pageNum int, pages []pageInformation, warnings []parseWarning) ([]pageInformation, []parseWarning, error) {
+	if s.minRows <= 0 || pageNum == s.lastPage || len(pages) >= s.minRows {
+		return pages, warnings, nil
+	}
+
+	if s.minRowsAction == "retry" {
+		for i := 0; i < minRowsRetries && len(pages) < s.minRows && ctx.Err() == nil; i++ {
+			log.Printf("page %d has only %d rows, retrying (%d of %d)\n", pageNum, len(pages), i+1, minRowsRetries)
+			retried, retriedWarnings, err := s.getListingPage(ctx, pageNum, false)
+			if err != nil {
+				continue
+			}
+			pages, warnings = retried, retriedWarnings
+		}
+		if len(pages) >= s.minRows {
+			return pages, warnings, nil
+		}
+	}
+
+	short := &shortPageError{pageNum: pageNum, rows: len(pages), min: s.minRows}
+	if s.minRowsAction == "skip" {
+		return nil, nil, short
+	}
+
+	log.Println("Warning:", short)
+	return pages, append(warnings, parseWarning{row: -1, reason: short.Error()}), nil
+}
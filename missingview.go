@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var missingViewActions = []string{"warn", "skip"}
+
+// 게시글 행은 있는데 viewSelector가 page 전체에서 하나도 맞지 않은 page를 action(warn, skip)으로 처리합니다.
+// 게시판에 따라 조회수가 다른 class의 칸에 나오면 모든 게시글의 view가 조용히 0이 되므로, selector가 틀렸다고 알려줍니다.
+//   - warn: 눈에 띄는 경고를 남기고 결과는 그대로 사용합니다. (기본값)
+//   - skip: 실패한 page로 처리합니다.
+func WithMissingViewAction(action string) Option {
+	return func(s *Scraper) {
+		s.missingViewAction = action
+	}
+}
+
+// 조회수 칸을 찾지 못한 page의 경고
+type missingViewError struct {
+	url  string
+	rows int
+}
+
+func (e *missingViewError) Error() string {
+	return fmt.Sprintf("%s: %q matched nothing in %d rows, so every view count would be 0 (the board layout may have changed, check with -explain)", e.url, viewSelector, e.rows)
+}
+
+// url에서 받은 목록 page doc에 조회수 칸이 있는지 확인합니다. skip이면 missingViewError를 리턴합니다.
+func (s *Scraper) checkViewColumn(doc *goquery.Document, url string, warnings []parseWarning) ([]parseWarning, error) {
+	rows := doc.Find(listingRowSelector).FilterFunction(func(_ int, row *goquery.Selection) bool {
+		return row.Find(titleSelector).Length() > 0
+	})
+	if rows.Length() == 0 || rows.Find(viewSelector).Length() > 0 {
+		return warnings, nil
+	}
+
+	missing := &missingViewError{url: url, rows: rows.Length()}
+	if s.missingViewAction == "skip" {
+		return nil, missing
+	}
+	log.Println("Warning:", missing)
+	return append(warnings, parseWarning{row: -1, reason: missing.Error()}), nil
+}
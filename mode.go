@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"sort"
+)
+
+// -mode 값별로 목록 URL에 붙일 query parameter. normal은 게시판 전체 목록입니다.
+// recommended는 같은 게시판의 추천글 목록(...?my=chu&p=3)이고, 게시글 행의 형식은 전체 목록과 같습니다.
+var boardModeParams = map[string]string{
+	"normal":      "",
+	"recommended": "my=chu",
+}
+
+func boardModeNames() []string {
+	names := []string{}
+	for name := range boardModeParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 수집할 목록을 정합니다. 기본은 normal이고, 수집한 게시글의 source에 이 이름이 들어갑니다.
+func WithMode(name string) Option {
+	return func(s *Scraper) {
+		s.mode = name
+	}
+}
+
+func (s *Scraper) source() string {
+	if s.mode == "" {
+		return "normal"
+	}
+	return s.mode
+}
+
+// 추천글 목록은 게시글 번호로 page 수를 짐작할 수 없으므로, page를 1, 2, 4, 8, ...로 늘려가며 확인해서
+// 게시글이 없는 page를 찾고, 그 사이를 이분 탐색해서 마지막 page를 찾습니다.
+func (s *Scraper) probeLastPage(ctx context.Context) (int, error) {
+	if ok, err := s.checkPageAvailable(ctx, s.PageURL(1)); err != nil || !ok {
+		return 0, err
+	}
+	return s.widenLastPage(ctx, 1)
+}
+
+// 게시글이 있는 page lo에서 시작해서 lo*2, lo*4, ...로 늘려가며 게시글이 없는 page를 찾고, 그 사이에서 마지막 page를 찾습니다.
+func (s *Scraper) widenLastPage(ctx context.Context, lo int) (int, error) {
+	hi := lo * 2
+	for {
+		ok, err := s.checkPageAvailable(ctx, s.PageURL(hi))
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		lo, hi = hi, hi*2
+	}
+	return s.lastPageBetween(ctx, lo, hi)
+}
+
+// lo는 게시글이 있는 page(없으면 0), hi는 게시글이 없는 page일 때 그 사이의 마지막 page를 이분 탐색으로 찾습니다.
+func (s *Scraper) lastPageBetween(ctx context.Context, lo, hi int) (int, error) {
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		ok, err := s.checkPageAvailable(ctx, s.PageURL(mid))
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
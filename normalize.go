@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 화면에는 보이지 않지만 문자열 비교를 방해하는 문자들 (zero-width, 방향 제어 문자)
+var invisibleReplacer = strings.NewReplacer(
+	"\u200b", "", "\u200c", "", "\u200d", "", "\u200e", "", "\u200f", "",
+	"\u202a", "", "\u202b", "", "\u202c", "", "\u202d", "", "\u202e", "",
+	"\u2060", "", "\u2066", "", "\u2067", "", "\u2068", "", "\u2069", "",
+	"\ufeff", "",
+)
+
+// 연속된 공백(탭, 줄바꿈 포함)을 공백 하나로 합치고 앞뒤 공백을 없앱니다.
+// stripInvisible이 true면 zero-width, 방향 제어 문자도 지웁니다.
+func normalizeText(s string, stripInvisible bool) string {
+	if stripInvisible {
+		s = invisibleReplacer.Replace(s)
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// 수집한 게시글의 제목과 글쓴이를 NFC로 바꿉니다. 자모가 나뉜(NFD) 한글도 같은 문자열로 비교되도록
+// 중복 제거, 필터, sink보다 먼저 page마다 적용합니다. (WithUnicodeNormalization)
+func normalizeUnicode(pages []pageInformation) {
+	for i := range pages {
+		pages[i].title = norm.NFC.String(pages[i].title)
+		pages[i].user = norm.NFC.String(pages[i].user)
+	}
+}
+
+// 수집한 게시글의 제목과 글쓴이를 page마다 NFC로 정규화합니다. (-normalize-unicode)
+func WithUnicodeNormalization(normalize bool) Option {
+	return func(s *Scraper) {
+		s.normalizeUnicode = normalize
+	}
+}
+
+// -normalize 옵션에서 사용하는 post processor. 제목과 글쓴이를 정규화합니다.
+func normalizeProcessor(stripInvisible bool) func([]pageInformation) ([]pageInformation, error) {
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		for i := range pages {
+			pages[i].title = normalizeText(pages[i].title, stripInvisible)
+			pages[i].user = normalizeText(pages[i].user, stripInvisible)
+		}
+		return pages, nil
+	}
+}
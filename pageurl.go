@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 목록 URL에 page 번호 parameter가 없을 때 사용하는 이름
+const defaultPageParam = "p"
+
+// 목록 page n의 URL을 만듭니다.
+// baseURL의 query에서 값이 비어 있는 마지막 parameter(...?p=)가 page 번호 자리이고, 없으면 p를 사용합니다.
+// 다른 parameter와 #fragment는 그대로 두고, -mode, -server-sort parameter는 page 번호 앞에 넣습니다.
+func (s *Scraper) PageURL(n int) string {
+	return s.buildPageURL(strconv.Itoa(n))
+}
+
+// -mode, -server-sort parameter. page 번호 앞에 이 순서로 넣습니다.
+func (s *Scraper) pageParams() []string {
+	extra := []string{}
+	for _, param := range []string{boardModeParams[s.mode], serverSortParams[s.serverSort]} {
+		if param != "" {
+			extra = append(extra, param)
+		}
+	}
+	return extra
+}
+
+func (s *Scraper) buildPageURL(page string) string {
+	extra := s.pageParams()
+	if s.pageTemplate != "" {
+		return expandPageTemplate(s.pageTemplate, s.baseURL, extra, page)
+	}
+
+	if s.base == nil {
+		// Validate에서 걸러지지만, 혹시 모르니 예전처럼 뒤에 붙입니다.
+		return s.baseURL + page
+	}
+	u := *s.base
+
+	params, pageParam := splitPageParam(u.RawQuery)
+	params = append(replaceParams(params, extra), pageParam+"="+page)
+	u.RawQuery = strings.Join(params, "&")
+	return u.String()
+}
+
+// params에서 extra와 이름이 같은 parameter를 지우고 extra를 뒤에 붙입니다.
+func replaceParams(params, extra []string) []string {
+	for _, param := range extra {
+		name, _, _ := strings.Cut(param, "=")
+		params = removeParam(params, name)
+	}
+	return append(params, extra...)
+}
+
+// page 번호가 path에 들어가는 게시판(/page/3)을 위해 page URL의 형식을 정합니다. (-page-template)
+// {base}는 -url에서 query와 #fragment를 뺀 부분이고, {n}은 page 번호입니다. ("{base}/page/{n}")
+// 비어 있으면 -url의 query에 page 번호를 넣습니다.
+func WithPageTemplate(template string) Option {
+	return func(s *Scraper) {
+		s.pageTemplate = template
+	}
+}
+
+// template의 {base}, {n}을 채웁니다. extra parameter가 있으면 query의 맨 앞에 넣습니다.
+func expandPageTemplate(template, baseURL string, extra []string, page string) string {
+	raw := strings.ReplaceAll(template, "{base}", templateBase(baseURL))
+	if len(extra) > 0 {
+		rest, fragment, hasFragment := strings.Cut(raw, "#")
+		path, query, _ := strings.Cut(rest, "?")
+		params := []string{}
+		if query != "" {
+			params = strings.Split(query, "&")
+		}
+		for _, param := range extra {
+			name, _, _ := strings.Cut(param, "=")
+			params = removeParam(params, name)
+		}
+		raw = path + "?" + strings.Join(append(extra, params...), "&")
+		if hasFragment {
+			raw += "#" + fragment
+		}
+	}
+	return strings.ReplaceAll(raw, "{n}", page)
+}
+
+func templateBase(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	return strings.TrimSuffix(u.String(), "/")
+}
+
+// query를 parameter 목록과 page 번호 parameter 이름으로 나눕니다. 리턴하는 목록에는 page parameter가 없습니다.
+func splitPageParam(rawQuery string) ([]string, string) {
+	params := []string{}
+	if rawQuery != "" {
+		params = strings.Split(rawQuery, "&")
+	}
+
+	pageParam := defaultPageParam
+	if last := len(params) - 1; last >= 0 && strings.HasSuffix(params[last], "=") {
+		pageParam = strings.TrimSuffix(params[last], "=")
+		params = params[:last]
+	}
+	return removeParam(params, pageParam), pageParam
+}
+
+func removeParam(params []string, name string) []string {
+	kept := []string{}
+	for _, param := range params {
+		if key, _, _ := strings.Cut(param, "="); key != name {
+			kept = append(kept, param)
+		}
+	}
+	return kept
+}
+
+// -print-url-template: page 번호가 URL에 어떻게 들어가는지 보여줍니다.
+// -to를 주지 않았다면 마지막 page를 알아야 하므로 실제로 게시판에 요청합니다.
+func printURLTemplate(ctx context.Context, s *Scraper, cfg Config) {
+	fmt.Println("template:   " + s.buildPageURL("{n}"))
+
+	last := cfg.To
+	if last == 0 {
+		var err error
+		last, err = s.getPages(ctx)
+		checkErr(err)
+	}
+	pageNums := s.pageRange(last)
+	if len(pageNums) == 0 {
+		fmt.Println("no pages in range")
+		return
+	}
+	fmt.Printf("first page: %s\n", s.PageURL(pageNums[0]))
+	fmt.Printf("last page:  %s\n", s.PageURL(pageNums[len(pageNums)-1]))
+
+	if s.pageTemplate != "" {
+		return
+	}
+	if warning := urlTemplateWarning(s.baseURL); warning != "" {
+		fmt.Println("warning:    " + warning)
+	}
+}
+
+// page 번호 parameter를 URL에서 찾지 못해서 이름을 추측했다면 이유를 리턴합니다.
+func urlTemplateWarning(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	if !strings.HasSuffix(u.RawQuery, "=") {
+		return fmt.Sprintf("the URL has no empty page parameter such as ?p=, so the page number is sent as ?%s=; if every page looks the same, add the board's page parameter to -url", defaultPageParam)
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 등록일을 알 수 없는 게시글이 들어가는 partition
+const unknownPartition = "unknown"
+
+// -partition-by date: 게시글의 등록일(2024-05-01)별로 나눕니다. RFC3339 시간이면 날짜 부분만 사용합니다.
+func datePartition(p pageInformation) string {
+	if len(p.date) < len("2006-01-02") {
+		return unknownPartition
+	}
+	day := p.date[:len("2006-01-02")]
+	if _, err := time.Parse("2006-01-02", day); err != nil {
+		return unknownPartition
+	}
+	return day
+}
+
+// 게시글을 partition 값별로 나눕니다. 각 partition 안의 순서는 pages의 순서 그대로이고,
+// 값은 오름차순으로 리턴합니다.
+func partitionPages(pages []pageInformation, partition func(pageInformation) string) ([]string, map[string][]pageInformation) {
+	groups := map[string][]pageInformation{}
+	for _, page := range pages {
+		value := partition(page)
+		groups[value] = append(groups[value], page)
+	}
+
+	values := []string{}
+	for value := range groups {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values, groups
+}
+
+// Hive 형식의 partition 경로. (out/pages.csv -> out/date=2024-05-01/pages.csv)
+func partitionPath(path, key, value string) string {
+	return filepath.Join(filepath.Dir(path), key+"="+value, filepath.Base(path))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// 외부 명령으로 결과를 후처리하는 post processor를 만듭니다.
+// 결과 전체를 JSON 배열로 명령의 stdin에 넘기고, stdout으로 돌려받은 JSON 배열을 새 결과로 사용합니다.
+// 명령은 shell을 거치지 않고 공백 기준으로 나눠서 실행합니다.
+func commandPostProcessor(command string) func([]pageInformation) ([]pageInformation, error) {
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("post-process: empty command")
+		}
+
+		input, err := json.Marshal(pages)
+		if err != nil {
+			return nil, fmt.Errorf("post-process: encoding input: %w", err)
+		}
+
+		var output bytes.Buffer
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Stdout = &output
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("post-process %q: %w", command, err)
+		}
+
+		processed := []pageInformation{}
+		if err := json.Unmarshal(output.Bytes(), &processed); err != nil {
+			return nil, fmt.Errorf("post-process %q: decoding output: %w", command, err)
+		}
+		return processed, nil
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// worker를 띄우기 전에 게시판 URL에 요청을 한 번 보내서, 200 응답과 HTML이 오는지 확인합니다.
+// URL 오타나 차단을 수집 도중이 아니라 시작할 때 알 수 있습니다.
+// HEAD를 지원하지 않는 서버라면 GET으로 다시 확인합니다.
+func (s *Scraper) Preflight(ctx context.Context) error {
+	res, err := s.request(ctx, http.MethodHead, s.baseURL)
+	if err == nil && (res.StatusCode == http.StatusMethodNotAllowed || res.StatusCode == http.StatusNotImplemented) {
+		res.Body.Close()
+		res, err = s.get(ctx, s.baseURL)
+	}
+	if err != nil {
+		return fmt.Errorf("preflight: %s is not reachable: %w", s.baseURL, err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("preflight: %s returned status %d, expected 200", s.baseURL, res.StatusCode)
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if !isHTMLContentType(contentType) {
+		return fmt.Errorf("preflight: %s returned Content-Type %q, expected HTML", s.baseURL, contentType)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // -pprof-addr에서 /debug/pprof/를 제공합니다.
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+)
+
+// -prof, -memprof, -pprof-addr에 따라 profiling을 시작합니다.
+// 리턴하는 함수는 CPU profile을 마무리하고 heap profile을 씁니다. 정상 종료할 때 한 번만 실행되면 되고, 여러 번 불러도 됩니다.
+func startProfiling(cfg Config) (func(), error) {
+	if cfg.PprofAddr != "" {
+		listener, err := net.Listen("tcp", cfg.PprofAddr)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("pprof: serving on http://%s/debug/pprof/\n", listener.Addr())
+		go http.Serve(listener, nil)
+	}
+
+	var cpuFile *os.File
+	if cfg.CPUProfile != "" {
+		file, err := os.Create(cfg.CPUProfile)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, err
+		}
+		cpuFile = file
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if cpuFile != nil {
+				pprof.StopCPUProfile()
+				if err := cpuFile.Close(); err != nil {
+					log.Println("pprof:", err)
+				}
+			}
+			if cfg.MemProfile != "" {
+				if err := writeHeapProfile(cfg.MemProfile); err != nil {
+					log.Println("pprof:", err)
+				}
+			}
+		})
+	}, nil
+}
+
+func writeHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// 마지막 GC 기준으로 기록되므로, 방금까지 할당한 내용이 반영되도록 GC를 한 번 실행합니다.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// proxy 하나를 확인하는 요청의 제한 시간
+const proxyTestTimeout = 10 * time.Second
+
+// 요청마다 proxy를 돌아가면서 고릅니다. (round robin)
+// 첫 proxy는 Scraper의 난수로 고르므로, 여러 번 실행해도 항상 같은 proxy부터 쓰지 않습니다. (-seed로 고정)
+type proxyPool struct {
+	mu      sync.Mutex
+	proxies []*url.URL
+	next    int
+	started bool
+	s       *Scraper
+}
+
+func (p *proxyPool) proxy(*http.Request) (*url.URL, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.started {
+		p.next = p.s.rng.Intn(len(p.proxies))
+		p.started = true
+	}
+	proxy := p.proxies[p.next%len(p.proxies)]
+	p.next++
+	return proxy, nil
+}
+
+// 요청을 proxies에 번갈아 보내는 http.Client를 사용합니다. proxies가 비어 있으면 아무것도 바꾸지 않습니다.
+func WithProxies(proxies []*url.URL) Option {
+	return func(s *Scraper) {
+		if len(proxies) == 0 {
+			return
+		}
+		pool := &proxyPool{proxies: proxies, s: s}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = pool.proxy
+		s.client = &http.Client{Transport: transport}
+	}
+}
+
+// -proxy 값을 URL로 바꿉니다. http, https, socks5 proxy만 받습니다.
+func parseProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (expected http, https or socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy %q has no host", raw)
+	}
+	return u, nil
+}
+
+// proxy 하나의 확인 결과
+type proxyHealth struct {
+	proxy   *url.URL
+	latency time.Duration
+	err     error // nil이면 사용할 수 있는 proxy
+}
+
+func (h proxyHealth) String() string {
+	if h.err != nil {
+		return fmt.Sprintf("%s: FAIL (%v)", h.proxy.Redacted(), h.err)
+	}
+	return fmt.Sprintf("%s: ok (%v)", h.proxy.Redacted(), h.latency.Round(time.Millisecond))
+}
+
+// 각 proxy를 통해 target에 요청을 한 번씩 보내서 사용할 수 있는지 확인합니다.
+// 결과는 proxies와 같은 순서로 리턴합니다.
+func checkProxies(ctx context.Context, proxies []*url.URL, target string) []proxyHealth {
+	results := make([]proxyHealth, len(proxies))
+
+	var wg sync.WaitGroup
+	for i, proxy := range proxies {
+		wg.Add(1)
+		go func(i int, proxy *url.URL) {
+			defer wg.Done()
+			start := time.Now()
+			err := checkProxy(ctx, proxy, target)
+			results[i] = proxyHealth{proxy: proxy, latency: time.Since(start), err: err}
+		}(i, proxy)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func checkProxy(ctx context.Context, proxy *url.URL, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, proxyTestTimeout)
+	defer cancel()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	res, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// http.Client의 기본값과 같은 redirect 횟수 제한
+const defaultMaxRedirects = 10
+
+// redirect를 따라가는 방법 (-max-redirects, -no-redirects)
+type redirectPolicy struct {
+	max    int  // 한 요청에서 따라갈 최대 redirect 수
+	follow bool // false면 redirect를 따라가지 않고 에러로 돌려줍니다.
+}
+
+// redirect를 따라가지 않았을 때의 에러. 게시판이 옮겨졌거나 로그인 page로 보내는 경우라서 재시도하지 않습니다.
+type redirectError struct {
+	url      string
+	location string
+	reason   string
+}
+
+func (e *redirectError) Error() string {
+	return fmt.Sprintf("%s redirected to %s: %s", e.url, e.location, e.reason)
+}
+
+// redirect를 따라갈지, 몇 번까지 따라갈지 정합니다. -v면 redirect마다 log를 남깁니다.
+// 정하지 않으면 http.Client의 설정을 그대로 사용합니다.
+func WithRedirects(max int, follow bool) Option {
+	return func(s *Scraper) {
+		s.redirects = &redirectPolicy{max: max, follow: follow}
+	}
+}
+
+// s.client를 복사해서 s.redirects를 따르는 CheckRedirect를 넣습니다. 넘겨받은 client는 바꾸지 않습니다.
+func (s *Scraper) applyRedirectPolicy() {
+	if s.redirects == nil {
+		return
+	}
+	client := *s.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		from := via[len(via)-1].URL.String()
+		if !s.redirects.follow {
+			return &redirectError{url: via[0].URL.String(), location: req.URL.String(), reason: "not followed (-no-redirects)"}
+		}
+		if len(via) > s.redirects.max {
+			return &redirectError{url: via[0].URL.String(), location: req.URL.String(), reason: fmt.Sprintf("stopped after %d redirects (-max-redirects)", s.redirects.max)}
+		}
+		if s.verbose {
+			log.Printf("redirect: %s -> %s\n", from, req.URL)
+		}
+		return nil
+	}
+	s.client = &client
+}
+
+func isRedirectError(err error) bool {
+	var redirect *redirectError
+	return errors.As(err, &redirect)
+}
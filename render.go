@@ -0,0 +1,27 @@
+package main
+
+import "time"
+
+// JavaScript로 목록을 그리는 게시판을 위한 Fetcher. headless Chrome으로 page를 열고,
+// 목록 행이 나타날 때까지 기다린 뒤 렌더링된 DOM을 돌려줍니다.
+// 실제 구현은 -tags chromedp로 빌드했을 때만 들어갑니다. (render_chromedp.go)
+type renderFetcher struct {
+	s *Scraper
+}
+
+// page 하나를 렌더링할 때의 기본 제한 시간. http.Client에 Timeout이 있으면 그 값을 사용합니다.
+const defaultRenderTimeout = 30 * time.Second
+
+// 목록 page를 headless browser로 렌더링해서 가져옵니다. Scraper의 rate limit을 그대로 따릅니다.
+func WithRender() Option {
+	return func(s *Scraper) {
+		s.fetcher = renderFetcher{s: s}
+	}
+}
+
+func (f renderFetcher) timeout() time.Duration {
+	if f.s.client.Timeout > 0 {
+		return f.s.client.Timeout
+	}
+	return defaultRenderTimeout
+}
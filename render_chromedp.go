@@ -0,0 +1,60 @@
+//go:build chromedp
+
+package main
+
+import (
+	"context"
+	neturl "net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+const renderSupported = true
+
+// page마다 browser를 새로 띄우므로 느리지만, 수집이 끝나거나 취소되면 Chrome도 같이 종료됩니다.
+func (f renderFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	ctx, cancelAlloc := chromedp.NewExecAllocator(ctx, chromedp.DefaultExecAllocatorOptions[:]...)
+	defer cancelAlloc()
+	ctx, cancelTab := chromedp.NewContext(ctx)
+	defer cancelTab()
+	ctx, cancel := context.WithTimeout(ctx, f.timeout())
+	defer cancel()
+
+	if f.s.hosts != nil {
+		u, err := neturl.Parse(url)
+		if err != nil {
+			return nil, err
+		}
+		release, err := f.s.hosts.acquire(ctx, u.Hostname())
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	if err := f.s.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	var html string
+	headers := network.Headers{}
+	if f.s.acceptLanguage != "" {
+		headers["Accept-Language"] = f.s.acceptLanguage
+	}
+	for name, values := range f.s.headers {
+		headers[name] = strings.Join(values, ", ")
+	}
+	err := chromedp.Run(ctx,
+		network.SetExtraHTTPHeaders(headers),
+		chromedp.Navigate(url),
+		chromedp.WaitReady(listingRowSelector, chromedp.ByQuery),
+		chromedp.OuterHTML("html", &html, chromedp.ByQuery),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return goquery.NewDocumentFromReader(strings.NewReader(html))
+}
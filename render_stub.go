@@ -0,0 +1,16 @@
+//go:build !chromedp
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const renderSupported = false
+
+func (f renderFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	return nil, errors.New("-render requires a binary built with -tags chromedp")
+}
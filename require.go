@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+)
+
+// 값이 있어야 하는 필드를 정합니다. 필드 하나라도 비어 있는 행은 parse 경고를 남기고 결과에서 뺍니다.
+// fail-fast라면 그 page를 실패로 처리해서 수집을 중단합니다. num은 공지처럼 번호가 없는(0) 행도 빠진 것으로 봅니다.
+func WithRequiredFields(fields []outputField) Option {
+	return func(s *Scraper) {
+		s.requiredFields = fields
+	}
+}
+
+// 필수 필드가 빠진 행이 있는데 fail-fast일 때의 에러
+type missingFieldError struct {
+	pageNum int
+	row     int
+	field   string
+}
+
+func (e *missingFieldError) Error() string {
+	return fmt.Sprintf("page %d row %d: required field %s is empty (-require-fields)", e.pageNum, e.row, e.field)
+}
+
+// 행에서 비어 있는 첫 필수 필드의 이름. 모두 값이 있으면 ""
+func missingField(page pageInformation, fields []outputField) string {
+	for _, f := range fields {
+		if f.value(page) == "" || (f.name == "num" && page.pageNum <= 0) {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// page 하나의 행 중 필수 필드가 빠진 행을 빼고, 뺀 행마다 parse 경고를 추가합니다.
+// 어차피 결과에서 빠지는 삭제된 게시글은 확인하지 않습니다.
+func (s *Scraper) checkRequiredFields(pageNum int, pages []pageInformation, warnings []parseWarning) ([]pageInformation, []parseWarning, int, error) {
+	if len(s.requiredFields) == 0 {
+		return pages, warnings, 0, nil
+	}
+
+	kept := pages[:0]
+	dropped := 0
+	for _, page := range pages {
+		field := ""
+		if !page.deleted || s.includeDeleted {
+			field = missingField(page, s.requiredFields)
+		}
+		if field == "" {
+			kept = append(kept, page)
+			continue
+		}
+		if s.failFast {
+			return nil, nil, 0, &missingFieldError{pageNum: pageNum, row: page.row, field: field}
+		}
+		warnings = append(warnings, parseWarning{row: page.row, reason: fmt.Sprintf("required field %s is empty, dropped", field)})
+		dropped++
+	}
+	return kept, warnings, dropped, nil
+}
+
+// -require-fields에 준 필드 목록
+func (c Config) requiredFields() []outputField {
+	fields := []outputField{}
+	for _, name := range c.RequireFields {
+		if f, exists := c.findField(name); exists {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 기본 RetryPolicy가 page 하나에 다시 요청하는 최대 횟수
+const defaultMaxRetries = 20
+
+// RetryPolicy는 목록 page 요청이 실패했을 때 다시 요청할지, 얼마나 기다린 뒤에 요청할지 정합니다.
+// attempt는 지금까지 실패한 횟수(처음 실패하면 1)이고, resp는 서버가 에러 status로 응답했을 때의 응답입니다. (없으면 nil)
+// resp의 Body는 이미 닫혀 있습니다.
+type RetryPolicy interface {
+	NextDelay(attempt int, err error, resp *http.Response) (time.Duration, bool)
+}
+
+// 기본 RetryPolicy. 다시 요청해볼 만한 에러(retryable)면 기다리지 않고 maxRetries번까지 다시 요청합니다.
+// 요청 간격은 rate limit(-rps, -adaptive, -throttle-on-error)이 정합니다.
+type defaultRetryPolicy struct {
+	maxRetries int
+}
+
+func (p defaultRetryPolicy) NextDelay(attempt int, err error, resp *http.Response) (time.Duration, bool) {
+	return 0, attempt <= p.maxRetries && retryable(err)
+}
+
+// 목록 page 요청의 재시도 정책을 정합니다. 정하지 않으면 defaultRetryPolicy를 사용합니다.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *Scraper) {
+		s.retry = policy
+	}
+}
+
+// 200이 아닌 status로 응답했을 때의 에러. RetryPolicy가 응답을 볼 수 있도록 응답을 같이 가지고 있습니다.
+type statusError struct {
+	res *http.Response
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with status %d", e.res.StatusCode)
+}
+
+// fn이 성공하거나, RetryPolicy가 그만하라고 할 때까지 fn을 다시 호출합니다. ctx가 취소되면 바로 마지막 에러를 리턴합니다.
+func (s *Scraper) withRetries(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		var resp *http.Response
+		var status *statusError
+		if errors.As(err, &status) {
+			resp = status.res
+		}
+		delay, retry := s.retry.NextDelay(attempt, err, resp)
+		if !retry {
+			return err
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			}
+		}
+	}
+}
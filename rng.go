@@ -0,0 +1,51 @@
+package main
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// Scraper 전체에서 함께 쓰는 난수 생성기. (-sample, proxy 순서 등)
+// 같은 seed로 만들면 같은 순서의 난수가 나오고, 여러 goroutine에서 써도 안전합니다.
+type lockedRand struct {
+	mu   sync.Mutex
+	seed int64
+	r    *rand.Rand
+}
+
+// seed가 0이면 crypto/rand로 seed를 정합니다. 정해진 seed는 Seed로 알 수 있습니다.
+func newLockedRand(seed int64) *lockedRand {
+	if seed == 0 {
+		var b [8]byte
+		crand.Read(b[:])
+		seed = int64(binary.LittleEndian.Uint64(b[:]) &^ (1 << 63))
+		if seed == 0 {
+			seed = 1
+		}
+	}
+	return &lockedRand{seed: seed, r: rand.New(rand.NewSource(seed))}
+}
+
+func (l *lockedRand) Seed() int64 {
+	return l.seed
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+func (l *lockedRand) Shuffle(n int, swap func(i, j int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.r.Shuffle(n, swap)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// "10%" 또는 "0.1" 형태의 sampling 비율을 0~1 사이 값으로 바꿉니다.
+func parseSampleRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	percent := strings.HasSuffix(s, "%")
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample %q: %w", s, err)
+	}
+	if percent {
+		rate /= 100
+	}
+	if rate <= 0 || rate > 1 {
+		return 0, fmt.Errorf("invalid sample %q: must be between 0%% and 100%%", s)
+	}
+	return rate, nil
+}
+
+// pageNums 중에서 rate 비율 또는 n개의 page를 무작위로 골라 오름차순으로 리턴합니다.
+// rate와 n이 모두 0이면 pageNums를 그대로 리턴합니다.
+// 비율로 고를 때에도 page가 하나라도 있으면 최소 1개는 고릅니다.
+func samplePages(pageNums []int, rate float64, n int, rng *lockedRand) []int {
+	if rate == 0 && n == 0 {
+		return pageNums
+	}
+
+	count := n
+	if rate > 0 {
+		count = int(float64(len(pageNums)) * rate)
+		if count == 0 && len(pageNums) > 0 {
+			count = 1
+		}
+	}
+	if count >= len(pageNums) {
+		return pageNums
+	}
+
+	sampled := make([]int, len(pageNums))
+	copy(sampled, pageNums)
+	rng.Shuffle(len(sampled), func(i, j int) {
+		sampled[i], sampled[j] = sampled[j], sampled[i]
+	})
+	sampled = sampled[:count]
+	sort.Ints(sampled)
+
+	return sampled
+}
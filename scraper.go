@@ -0,0 +1,752 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	neturl "net/url"
+	"sort"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/sync/errgroup"
+)
+
+// Scraper는 게시판 전체를 수집하는 과정을 묶어둔 타입입니다.
+type Scraper struct {
+	baseURL  string
+	base     *neturl.URL // 파싱한 baseURL. baseURL이 올바른 URL이 아니면 nil
+	from     int
+	to       int
+	maxPages int
+
+	firstPageOnly bool
+
+	perPage         int // WithPostsPerPage로 정한 목록 page 하나의 게시글 수. 0이면 detectedPerPage를 씁니다.
+	detectedPerPage int // 마지막 Scrape에서 getPages가 첫 page에서 센 게시글 수
+
+	pageTemplate   string // 비어 있으면 baseURL의 query에 page 번호를 넣습니다.
+	jsonEndpoint   string // 비어 있지 않으면 목록 page를 이 URL의 JSON에서 읽습니다.
+	jsonListKey    string
+	snapshotDir    string // 비어 있지 않으면 받은 목록 page의 HTML을 저장합니다.
+	snapshotSource string // 비어 있지 않으면 요청하지 않고 이 디렉토리의 snapshot을 파싱합니다.
+	mode           string // 비어 있거나 normal이면 게시판 전체 목록
+	serverSort     string // 비어 있거나 recent면 게시판 기본 순서
+	originalOrder  bool   // 게시글 번호 대신 게시판에 보이는 순서로 돌려줍니다.
+	limit          int
+
+	client         *http.Client
+	redirects      *redirectPolicy // nil이면 client의 CheckRedirect를 그대로 사용합니다.
+	acceptLanguage string          // 비어 있으면 Accept-Language header를 보내지 않습니다.
+	headers        http.Header     // -header로 준, 모든 요청에 추가로 보낼 header
+	fetcher        Fetcher         // nil이면 client로 요청하는 httpFetcher를 사용합니다.
+	block          blockRules
+
+	extraFields []extraField
+	badges      map[string]string // badge 이름 -> selector. nil이면 수집하지 않습니다.
+
+	intervalJitter float64 // -interval 사이에 기다리는 시간을 무작위로 바꾸는 비율
+
+	// 목록 page를 파싱하는 함수. 기본은 parsePage이고, 테스트에서 바꿉니다.
+	parse func(doc *goquery.Document, base *neturl.URL, keepEmpty bool, now time.Time) ([]pageInformation, []parseWarning)
+
+	postProcessors []func([]pageInformation) ([]pageInformation, error)
+
+	sampleRate float64
+	sampleN    int
+
+	rng *lockedRand
+
+	workers   int
+	limiter   *rateLimiter
+	hosts     *hostLimiter
+	pageSleep time.Duration // worker가 page 하나를 끝낼 때마다 쉬는 시간
+	adaptive  *adaptiveController
+	throttle  *throttleController
+
+	includeDeleted bool
+
+	normalizeUnicode bool // 제목과 글쓴이를 NFC로 바꿉니다.
+	keepEmpty        bool
+
+	minRows       int
+	minRowsAction string
+
+	missingViewAction string
+	lastPage          int // 마지막 Scrape에서 찾은 마지막 page. 행이 적어도 정상이므로 min-rows 확인에서 뺍니다.
+	dedup             bool
+	dedupFields       []outputField // -dedup-key. 비어 있으면 번호로 중복을 구분합니다.
+
+	audit *auditLog // nil이면 요청을 기록하지 않습니다.
+	sink  EventSink // nil이면 파일로만 씁니다.
+
+	imageDir   string // 비어 있지 않으면 수집이 끝난 뒤 썸네일을 내려받습니다.
+	bodyLength bool   // 수집이 끝난 뒤 게시글 page마다 본문 글자 수를 셉니다.
+
+	confirmThreshold int
+	confirm          func(pages int) bool // nil이면 묻지 않습니다.
+
+	failFast    bool
+	maxFailures int   // 0이 아니면 실패한 page가 이보다 많아질 때 중단합니다.
+	aborted     error // maxFailures를 넘어서 중단했을 때의 tooManyFailuresError
+	retryRounds int
+	retryDelay  time.Duration // -retry-failures의 라운드 n에서는 n * retryDelay만큼 기다립니다.
+	retry       RetryPolicy   // 목록 page 요청을 다시 보낼지 정합니다.
+	pageTimeout time.Duration // 0이 아니면 page 하나(재시도 포함)에 쓰는 시간의 상한
+	keepWarm    time.Duration // 0이 아니면 -interval 실행 사이에 연결을 유지합니다. (실행 간격)
+	failed      []int         // 마지막 Scrape에서 수집에 실패한 page 번호
+	warnings    []parseWarning
+	dropped     int // 마지막 Scrape에서 필수 필드가 빠져서 뺀 행 수
+
+	requiredFields []outputField // 값이 있어야 하는 필드. 빠진 행은 결과에서 뺍니다.
+
+	verbose bool
+
+	closed bool
+
+	now       func() time.Time // 테스트에서 시간을 고정할 때 바꿉니다.
+	startedAt time.Time        // 마지막 Scrape를 시작한 시간. 상대 시간("5분 전")의 기준입니다.
+
+	collected int // post processor를 거치기 전에 수집된 게시글 수
+
+	stats scrapeStats // 마지막 Scrape에서 수집한 게시글 합계 (dedup, post processor 전)
+}
+
+type Option func(*Scraper)
+
+func NewScraper(opts ...Option) *Scraper {
+	s := &Scraper{from: 1, client: http.DefaultClient, workers: 1, limiter: &rateLimiter{}, block: defaultBlockRules(), acceptLanguage: defaultAcceptLanguage, retry: defaultRetryPolicy{maxRetries: defaultMaxRetries}, retryDelay: retryFailuresDelay, parse: parsePage, rng: newLockedRand(0), now: time.Now}
+	WithBaseURL(defaultBaseURL)(s)
+	for _, opt := range opts {
+		opt(s)
+	}
+	// WithHTTPClient, WithProxies와 순서에 상관없이 적용되도록 옵션을 모두 적용한 뒤에 client를 바꿉니다.
+	s.applyRedirectPolicy()
+	s.applyKeepWarm()
+	return s
+}
+
+// 수집이 끝난 뒤, 파일로 쓰기 전에 결과를 변환/보강/필터링하는 함수를 등록합니다.
+// 여러 개를 등록하면 등록한 순서대로 실행되고, 하나라도 에러를 리턴하면 Scrape가 중단됩니다.
+func WithPostProcessor(fn func([]pageInformation) ([]pageInformation, error)) Option {
+	return func(s *Scraper) {
+		s.postProcessors = append(s.postProcessors, fn)
+	}
+}
+
+// 수집할 게시판 목록 URL을 정합니다. page 번호는 이 URL 뒤에 붙습니다.
+func WithBaseURL(baseURL string) Option {
+	return func(s *Scraper) {
+		s.baseURL = baseURL
+		// page URL을 만들 때마다 다시 파싱하지 않도록 한 번만 파싱해 둡니다.
+		s.base, _ = neturl.Parse(baseURL)
+	}
+}
+
+// from ~ to page만 수집합니다. to가 0이면 마지막 page까지 수집합니다.
+func WithPageRange(from, to int) Option {
+	return func(s *Scraper) {
+		s.from = from
+		s.to = to
+	}
+}
+
+// 요청에 사용할 http.Client를 정합니다. 테스트에서 httptest 서버의 client를 넣을 때 사용합니다.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Scraper) {
+		s.client = client
+	}
+}
+
+// 마지막 page를 찾지 않고 첫 page만 요청해서 수집합니다. 최신 게시글만 필요하거나 selector를 빨리 확인할 때 사용합니다.
+func WithFirstPageOnly(enabled bool) Option {
+	return func(s *Scraper) {
+		s.firstPageOnly = enabled
+	}
+}
+
+// 발견한 마지막 page나 page 범위와 상관없이 최대 n개의 page만 수집합니다. 0이면 제한하지 않습니다.
+func WithMaxPages(n int) Option {
+	return func(s *Scraper) {
+		s.maxPages = n
+	}
+}
+
+// 목록 page 하나의 게시글 수(공지 제외)를 정합니다. 0이면 첫 page의 게시글 수를 세어서 사용합니다.
+func WithPostsPerPage(n int) Option {
+	return func(s *Scraper) {
+		s.perPage = n
+	}
+}
+
+// 마지막 page를 짐작하거나 -limit에 필요한 page 수를 계산할 때 쓰는 page당 게시글 수
+func (s *Scraper) postsPerPage() int {
+	if s.perPage > 0 {
+		return s.perPage
+	}
+	if s.detectedPerPage > 0 {
+		return s.detectedPerPage
+	}
+	return defaultPostsPerPage
+}
+
+// 동시에 수집할 page 수를 정합니다.
+func WithWorkers(n int) Option {
+	return func(s *Scraper) {
+		if n > 0 {
+			s.workers = n
+		}
+	}
+}
+
+// 초당 요청 수를 rps로 제한합니다. 0이면 제한하지 않습니다.
+func WithRateLimit(rps float64) Option {
+	return func(s *Scraper) {
+		s.limiter.SetRate(rps)
+	}
+}
+
+// worker가 page 하나를 수집할 때마다 d만큼 쉽니다. rate limit은 다음 요청 전에 따로 기다리므로,
+// 한 worker의 요청 간격은 d와 rate limit 간격 중 긴 쪽이 됩니다. 0이면 쉬지 않습니다.
+func WithPageSleep(d time.Duration) Option {
+	return func(s *Scraper) {
+		s.pageSleep = d
+	}
+}
+
+// 응답 시간과 에러 비율을 보고 요청 속도를 minRPS ~ maxRPS 사이에서 조절합니다.
+// WithRateLimit으로 정한 속도에서 시작하고, 정하지 않았다면 minRPS에서 시작합니다.
+func WithAdaptiveRate(minRPS, maxRPS float64) Option {
+	return func(s *Scraper) {
+		s.adaptive = &adaptiveController{limiter: s.limiter, minRate: minRPS, maxRate: maxRPS}
+	}
+}
+
+// 최근 요청의 실패 비율이 높아지면 실행 전체의 요청 속도를 줄이고, 성공하면 WithRateLimit의 속도까지 천천히 되돌립니다.
+// WithRateLimit으로 속도를 정해야 동작합니다.
+func WithThrottleOnError(settings throttleSettings) Option {
+	return func(s *Scraper) {
+		s.throttle = &throttleController{limiter: s.limiter, settings: settings}
+	}
+}
+
+// 수집 대상 page 중 rate 비율(0~1) 또는 n개만 무작위로 골라서 수집합니다.
+// WithSeed로 seed를 정하면 항상 같은 page들이 골라집니다.
+func WithSample(rate float64, n int) Option {
+	return func(s *Scraper) {
+		s.sampleRate = rate
+		s.sampleN = n
+	}
+}
+
+// 무작위로 동작하는 모든 기능(-sample, proxy 순서)이 쓰는 난수 생성기의 seed를 정합니다.
+// 0이면 실행할 때마다 다른 seed를 사용하고, 사용한 seed는 Seed로 알 수 있습니다.
+func WithSeed(seed int64) Option {
+	return func(s *Scraper) {
+		s.rng = newLockedRand(seed)
+	}
+}
+
+// 이번 Scraper가 사용하는 seed. 같은 결과를 다시 얻으려면 이 값을 WithSeed(-seed)로 주면 됩니다.
+func (s *Scraper) Seed() int64 {
+	return s.rng.Seed()
+}
+
+// from ~ to 범위를 발견된 마지막 page에 맞춰 잘라서 수집할 page 번호 목록을 만듭니다.
+func (s *Scraper) pageRange(maxPageNum int) []int {
+	from, to := s.from, s.to
+	if from < 1 {
+		from = 1
+	}
+	if to == 0 || to > maxPageNum {
+		to = maxPageNum
+	}
+
+	if s.maxPages > 0 && to-from+1 > s.maxPages {
+		log.Printf("-max-pages %d applied: scraping pages %d-%d instead of %d-%d\n", s.maxPages, from, from+s.maxPages-1, from, to)
+		to = from + s.maxPages - 1
+	}
+
+	if s.limit > 0 {
+		if needed := (s.limit + s.postsPerPage() - 1) / s.postsPerPage(); to-from+1 > needed {
+			to = from + needed - 1
+		}
+	}
+
+	pageNums := []int{}
+	for i := from; i <= to; i++ {
+		pageNums = append(pageNums, i)
+	}
+	return pageNums
+}
+
+// 같은 게시글이 여러 page에서 수집되면 하나만 남깁니다. (목록이 밀리면서 같은 글이 다음 page에 다시 나올 수 있음)
+func WithDedup(dedup bool) Option {
+	return func(s *Scraper) {
+		s.dedup = dedup
+	}
+}
+
+// 모든 요청의 URL, 시도 횟수, 상태 코드, 크기, 걸린 시간, 에러를 audit log에 기록합니다.
+// audit log는 Scraper가 가져가서 Close할 때 닫습니다.
+func WithAuditLog(audit *auditLog) Option {
+	return func(s *Scraper) {
+		s.audit = audit
+	}
+}
+
+// 삭제된 게시글(목록에 자리만 남은 글)을 결과에 포함할지 정합니다. 기본은 건너뜁니다.
+func WithDeleted(include bool) Option {
+	return func(s *Scraper) {
+		s.includeDeleted = include
+	}
+}
+
+// post processor까지 거친 최종 결과의 썸네일을 dir에 내려받습니다. (게시글 번호로 파일 이름을 정합니다)
+func WithImageDownload(dir string) Option {
+	return func(s *Scraper) {
+		s.imageDir = dir
+	}
+}
+
+// page 하나라도 수집에 실패하면 나머지 요청을 취소하고 Scrape가 에러를 리턴하도록 합니다.
+// 기본(false)은 실패한 page를 건너뛰고 나머지를 계속 수집합니다.
+func WithFailFast(failFast bool) Option {
+	return func(s *Scraper) {
+		s.failFast = failFast
+	}
+}
+
+// 실패한 page가 n개를 넘으면 나머지 요청을 취소하고, 그때까지 수집한 결과와 tooManyFailuresError를 리턴하도록 합니다.
+// WithFailFast와 달리 결과는 취소되었을 때처럼 정렬과 후처리를 거칩니다. 0이면 제한하지 않습니다.
+func WithMaxFailures(n int) Option {
+	return func(s *Scraper) {
+		s.maxFailures = n
+	}
+}
+
+// 실패한 page가 -max-failures-abort를 넘어서 중단했을 때의 에러
+type tooManyFailuresError struct {
+	failed int
+	max    int
+}
+
+func (e *tooManyFailuresError) Error() string {
+	return fmt.Sprintf("aborted after %d pages failed (more than -max-failures-abort %d); the output is incomplete", e.failed, e.max)
+}
+
+func (e *tooManyFailuresError) Is(target error) bool {
+	return target == ErrPartial
+}
+
+func isTooManyFailures(err error) bool {
+	var tooMany *tooManyFailuresError
+	return errors.As(err, &tooMany)
+}
+
+// 수집이 끝난 뒤 실패한 page만 최대 rounds번 더 수집합니다. 라운드 n에서는 n * retryFailuresDelay만큼 기다린 뒤 시작합니다.
+// proxy를 여러 개 쓰고 있다면 다시 시도할 때는 다른 proxy로 요청이 나갑니다.
+func WithRetryFailures(rounds int) Option {
+	return func(s *Scraper) {
+		s.retryRounds = rounds
+	}
+}
+
+// page 하나를 수집하는 데 쓰는 시간을 재시도까지 포함해서 d로 제한합니다. 시간을 넘긴 page는 실패로 처리하고
+// 다음 page로 넘어갑니다. (keep-going) 0이면 제한하지 않습니다.
+func WithPageTimeout(d time.Duration) Option {
+	return func(s *Scraper) {
+		s.pageTimeout = d
+	}
+}
+
+// page 하나가 -timeout-per-page 안에 끝나지 않았을 때의 에러
+type pageTimeoutError struct {
+	pageNum int
+	timeout time.Duration
+}
+
+func (e *pageTimeoutError) Error() string {
+	return fmt.Sprintf("page %d: gave up after %v including retries (-timeout-per-page)", e.pageNum, e.timeout)
+}
+
+func (e *pageTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// -retry-failures의 라운드 사이 기본 대기 시간
+const retryFailuresDelay = 5 * time.Second
+
+// 게시판은 Accept-Language에 따라 다른 언어나 다른 markup을 돌려줄 수 있으므로, 기본으로 한국어를 요청합니다.
+const defaultAcceptLanguage = "ko-KR,ko;q=0.9"
+
+// 모든 요청에 보낼 Accept-Language header를 정합니다. 빈 문자열이면 보내지 않습니다.
+func WithAcceptLanguage(acceptLanguage string) Option {
+	return func(s *Scraper) {
+		s.acceptLanguage = acceptLanguage
+	}
+}
+
+// 자세한 진단 정보(HTML이 아닌 응답의 앞부분 등)를 log로 남깁니다.
+func WithVerbose(verbose bool) Option {
+	return func(s *Scraper) {
+		s.verbose = verbose
+	}
+}
+
+// 제목 링크가 없는 행도 빈 제목, 빈 링크로 결과에 남깁니다. 기본은 건너뛰고 parse 경고로 남깁니다.
+func WithKeepEmpty(keep bool) Option {
+	return func(s *Scraper) {
+		s.keepEmpty = keep
+	}
+}
+
+// 마지막 Scrape에서 목록을 파싱하면서 나온 경고를 목록 page, 행 순서로 리턴합니다.
+func (s *Scraper) Warnings() []parseWarning {
+	return s.warnings
+}
+
+// 마지막 Scrape에서 수집에 실패한 page 번호를 오름차순으로 리턴합니다.
+func (s *Scraper) Failed() []int {
+	return s.failed
+}
+
+// 마지막 Scrape에서 post processor를 거치기 전에 수집된 게시글 수를 리턴합니다.
+// Scrape 결과가 비어 있을 때 게시판이 비어 있었는지, 필터링으로 모두 빠졌는지 구분할 때 사용합니다.
+func (s *Scraper) Collected() int {
+	return s.collected
+}
+
+// 마지막 Scrape에서 수집한 게시글의 합계를 리턴합니다. 수집하면서 쌓은 값이라 dedup과 post processor 전 기준입니다.
+// 삭제된 게시글은 WithDeleted(true)일 때만 포함됩니다.
+func (s *Scraper) Stats() scrapeStats {
+	return s.stats
+}
+
+// rate limit을 지켜서 GET 요청을 보내고, adaptive 모드라면 응답 시간과 결과를 기록합니다.
+func (s *Scraper) get(ctx context.Context, url string) (*http.Response, error) {
+	return s.request(ctx, http.MethodGet, url)
+}
+
+func (s *Scraper) request(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", s.acceptLanguage)
+	}
+	s.setHeaders(req)
+
+	release := func() {}
+	if s.hosts != nil {
+		if release, err = s.hosts.acquire(ctx, req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.limiter.Wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := s.client.Do(req)
+	if err != nil {
+		release()
+	} else {
+		res.Body = &hostSlotBody{ReadCloser: res.Body, release: release}
+	}
+	failed := err != nil || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	if s.adaptive != nil {
+		s.adaptive.observe(time.Since(start), failed)
+	}
+	// 취소되어 실패한 요청은 서버 상태와 상관이 없으므로 세지 않습니다.
+	if s.throttle != nil && ctx.Err() == nil {
+		s.throttle.observe(failed)
+	}
+
+	if s.audit != nil {
+		event := auditEvent{Time: start, Method: method, URL: url, Attempt: s.audit.nextAttempt(url)}
+		if err != nil {
+			event.Error = err.Error()
+			event.DurationMS = time.Since(start).Milliseconds()
+			s.audit.write(event)
+		} else {
+			event.Status = res.StatusCode
+			res.Body = &auditBody{ReadCloser: res.Body, log: s.audit, event: event, start: start}
+		}
+	}
+
+	return res, err
+}
+
+// Scraper가 가진 자원을 정리합니다. sink에 남은 message를 보내고, audit log를 디스크에 쓰고 닫고,
+// client의 남은 연결을 끊습니다.
+// Close한 뒤에는 Scraper를 사용하면 안 되고, Scrape는 errScraperClosed를 리턴합니다.
+// 여러 번 호출해도 됩니다.
+func (s *Scraper) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var err error
+	if s.sink != nil {
+		err = s.sink.Close()
+	}
+	if s.audit != nil {
+		if auditErr := s.audit.Close(); err == nil {
+			err = auditErr
+		}
+	}
+	s.client.CloseIdleConnections()
+	return err
+}
+
+// 등록일의 상대 시간을 바꿀 때 기준이 되는 시간. Scrape 중이면 시작한 시간이고, 아니면 지금입니다.
+func (s *Scraper) referenceTime() time.Time {
+	if s.startedAt.IsZero() {
+		return s.now()
+	}
+	return s.startedAt
+}
+
+var errScraperClosed = errors.New("scraper is closed")
+
+// 게시판을 수집해서 결과를 리턴합니다.
+// 게시판이 로그인/captcha/차단 page를 돌려주면 설정과 상관없이 바로 중단하고 blockedError를 리턴합니다.
+// page 수집이 실패해도 기본(keep-going)으로는 나머지 page를 계속 수집하고, 실패한 page는 Failed로 알 수 있습니다.
+// WithFailFast로 설정했다면 처음 실패한 page에서 나머지 요청을 취소하고 에러를 리턴합니다.
+// ctx가 취소되면 그때까지 수집한 결과와 함께 ctx의 에러를 리턴합니다.
+// 에러는 errors.Is로 ErrTimeout, ErrBlocked, ErrNoPages, ErrPartial인지 확인할 수 있습니다.
+func (s *Scraper) Scrape(ctx context.Context) ([]pageInformation, error) {
+	results, err := s.scrape(ctx)
+	return results, timeoutError(err)
+}
+
+func (s *Scraper) scrape(ctx context.Context) ([]pageInformation, error) {
+	if s.closed {
+		return nil, errScraperClosed
+	}
+	s.startedAt = s.now()
+
+	var pageNums []int
+	if s.snapshotSource != "" {
+		var err error
+		if pageNums, err = s.snapshotPages(); err != nil {
+			return nil, err
+		}
+	} else if s.firstPageOnly {
+		// 마지막 page는 모르는 채로 둡니다.
+		s.lastPage = 0
+		pageNums = []int{1}
+	} else {
+		if s.fetcher == nil {
+			if err := s.Preflight(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		maxPageNum, err := s.getPages(ctx) // 최대 page를 계산해서 받아오는 부분
+		if err != nil {
+			return nil, err
+		}
+		s.lastPage = maxPageNum
+		fmt.Println(fmt.Sprint(maxPageNum) + "pages found")
+
+		pageNums = s.pageRange(maxPageNum)
+	}
+	if s.sampleRate > 0 || s.sampleN > 0 {
+		total := len(pageNums)
+		pageNums = samplePages(pageNums, s.sampleRate, s.sampleN, s.rng)
+		fmt.Printf("sampling %d of %d pages (seed %d): output is a sample, not the full board\n", len(pageNums), total, s.Seed())
+	}
+	if s.confirm != nil && len(pageNums) > s.confirmThreshold && !s.confirm(len(pageNums)) {
+		return nil, errNotConfirmed
+	}
+
+	if s.adaptive != nil {
+		s.adaptive.start()
+	}
+	if s.throttle != nil {
+		s.throttle.start()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.failed = nil
+	s.aborted = nil
+	s.warnings = nil
+	s.dropped = 0
+	s.stats = scrapeStats{}
+	results, firstErr := s.collect(ctx, cancel, pageNums)
+
+	// keep-going으로 실패한 page가 남았다면 그 page들만 다시 수집합니다. 라운드마다 조금 더 오래 기다립니다.
+	for round := 1; round <= s.retryRounds && len(s.failed) > 0 && firstErr == nil && ctx.Err() == nil; round++ {
+		delay := time.Duration(round) * s.retryDelay
+		fmt.Printf("retrying %d failed pages in %v (round %d of %d)\n", len(s.failed), delay, round, s.retryRounds)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+
+		failed := s.failed
+		s.failed = nil
+		retried, err := s.collect(ctx, cancel, failed)
+		results = append(results, retried...)
+		firstErr = err
+	}
+	sort.Ints(s.failed)
+	sortWarnings(s.warnings)
+
+	if firstErr != nil {
+		return results, incompleteError(firstErr)
+	}
+	// 중간에 취소되었다면 그때까지 수집한 결과도 정렬과 후처리를 거쳐서 ctx의 에러와 함께 돌려줍니다.
+	runErr := incompleteError(ctx.Err())
+	if s.aborted != nil {
+		runErr = s.aborted
+	}
+	if runErr == nil && len(s.failed) > 0 {
+		fmt.Printf("%d pages failed: %v\n", len(s.failed), s.failed)
+	}
+	if s.dropped > 0 {
+		fmt.Printf("%d rows dropped by -require-fields (see the parse warnings)\n", s.dropped)
+	}
+
+	sortPages(results)
+
+	if s.dedup {
+		results = dedupPages(results, s.dedupFields)
+	}
+
+	if !s.includeDeleted {
+		kept := results[:0]
+		for _, page := range results {
+			if !page.deleted {
+				kept = append(kept, page)
+			}
+		}
+		if skipped := len(results) - len(kept); skipped > 0 {
+			fmt.Println(fmt.Sprint(skipped) + " deleted posts skipped")
+		}
+		results = kept
+	}
+
+	if s.serverSort != "" || s.limit > 0 || s.originalOrder {
+		sortPagesByListing(results)
+	}
+	if s.limit > 0 {
+		results = limitPages(results, s.limit)
+		if s.serverSort == "" && !s.originalOrder {
+			sortPages(results)
+		}
+	}
+
+	s.collected = len(results)
+
+	for i, fn := range s.postProcessors {
+		processed, err := fn(results)
+		if err != nil {
+			return nil, fmt.Errorf("post processor #%d failed: %w", i+1, err)
+		}
+		results = processed
+	}
+
+	if s.imageDir != "" && runErr == nil {
+		if err := s.downloadImages(ctx, results); err != nil {
+			return nil, err
+		}
+	}
+	if s.bodyLength && runErr == nil {
+		s.collectBodyLengths(ctx, results)
+	}
+
+	return results, runErr
+}
+
+// pageNums를 worker들에게 나눠서 수집하고, 성공한 page의 게시글을 리턴합니다.
+// 실패한 page는 s.failed에 추가하고 집계는 s.stats에 더합니다.
+// failFast라면 처음 실패한 page의 에러를 리턴하고 cancel로 나머지 요청을 취소합니다.
+func (s *Scraper) collect(ctx context.Context, cancel context.CancelFunc, pageNums []int) ([]pageInformation, error) {
+	results := []pageInformation{}
+	c := make(chan pageResult)
+
+	// 동시에 수집하는 page는 s.workers개입니다. fail-fast면 처음 실패한 page의 에러로 group의 ctx가 취소되어
+	// 남은 page는 요청하지 않고, keep-going이면 실패한 page를 s.failed에 모으고 계속 수집합니다.
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(s.workers)
+
+	// 취소된 뒤에도 page 번호는 모두 넘깁니다. 요청하지 않고 바로 실패로 돌려주므로 s.failed에 남습니다.
+	// 모든 page가 끝나면 c를 닫습니다. 결과 수를 세지 않고 c가 닫힐 때까지 받으므로 멈추거나 결과를 흘리지 않습니다.
+	go func() {
+		for n, pageNum := range pageNums {
+			group.Go(func() error {
+				// -sleep-between-pages: worker 수만큼의 첫 page 뒤부터, 다음 page를 받기 전에 쉽니다.
+				if n >= s.workers {
+					s.sleepBetweenPages(groupCtx)
+				}
+				result := s.scrapePage(groupCtx, pageNum)
+				c <- result
+				if s.failFast && result.err != nil {
+					return result.err
+				}
+				return nil
+			})
+		}
+		group.Wait()
+		close(c)
+	}()
+
+	var firstErr error
+	for result := range c {
+		if result.err != nil {
+			s.failed = append(s.failed, result.pageNum)
+			// 차단되었다면 나머지 page도 같은 결과이므로 fail-fast가 아니어도 중단합니다.
+			if firstErr == nil && (s.failFast || isBlocked(result.err)) && ctx.Err() == nil {
+				firstErr = fmt.Errorf("page %d: %w", result.pageNum, result.err)
+				cancel()
+			}
+			if s.maxFailures > 0 && s.aborted == nil && len(s.failed) > s.maxFailures && ctx.Err() == nil {
+				s.aborted = &tooManyFailuresError{failed: len(s.failed), max: s.maxFailures}
+				cancel()
+			}
+			continue
+		}
+		results = append(results, result.pages...)
+		s.warnings = append(s.warnings, result.warnings...)
+		s.dropped += result.dropped
+
+		if s.sink != nil && firstErr == nil {
+			if err := s.sink.Publish(ctx, result.pages); err != nil && ctx.Err() == nil {
+				firstErr = fmt.Errorf("sink: page %d: %w", result.pageNum, err)
+				cancel()
+			}
+		}
+
+		s.stats.pages++
+		for _, page := range result.pages {
+			if !page.deleted || s.includeDeleted {
+				s.stats.add(page)
+			}
+		}
+	}
+
+	return results, firstErr
+}
+
+// WithPageSleep으로 정한 시간만큼 쉽니다. ctx가 취소되면 바로 돌아옵니다.
+func (s *Scraper) sleepBetweenPages(ctx context.Context) {
+	if s.pageSleep <= 0 || ctx.Err() != nil {
+		return
+	}
+	timer := time.NewTimer(s.pageSleep)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
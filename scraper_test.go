@@ -0,0 +1,2214 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/unicode/norm"
+)
+
+const fixtureBoardURL = "https://www.inven.co.kr/board/ff14/4337"
+
+// p 쿼리 값에 맞는 testdata/*.html 파일을 돌려주는 서버를 띄웁니다.
+// pages에 없는 page를 요청하면 empty.html(no-result)을 돌려줍니다.
+func newFixtureServer(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, exists := pages[r.URL.Query().Get("p")]
+		if !exists {
+			name = "empty.html"
+		}
+
+		body, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Errorf("reading fixture %s: %v", name, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// fixtureNow는 fixture를 파싱할 때 기준이 되는 시간입니다. (등록일 "05-01", "5분 전" 등)
+var fixtureNow = time.Date(2024, 5, 10, 12, 0, 0, 0, boardLocation)
+
+func newFixtureScraper(server *httptest.Server) *Scraper {
+	s := NewScraper(WithBaseURL(server.URL+"/board/ff14/4337?p="), WithHTTPClient(server.Client()))
+	s.now = func() time.Time { return fixtureNow }
+	return s
+}
+
+func TestGetPageTitle(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		want     []pageInformation
+		warnings int
+	}{
+		{
+			fixture: "normal.html",
+			want: []pageInformation{
+				{pageNum: 65, title: "오늘 레이드 후기", user: "모그리", view: 1234, comments: 12, date: "2024-05-01", link: fixtureBoardURL + "/65", category: "잡담", thumbnail: "http://upload3.inven.co.kr/upload/2024/05/01/bbs/i65.jpg"},
+				{pageNum: 64, title: "템 세팅 질문드립니다", user: "초코보", view: 87, date: "2024-05-01", link: fixtureBoardURL + "/64"},
+				{pageNum: 63, title: "패치 노트 정리", user: "라라펠", view: 12005, comments: 3, recommend: 5, date: "2024-05-01", link: fixtureBoardURL + "/63", category: "정보", thumbnail: "{server}/upload/2024/05/01/bbs/i63.jpg"},
+			},
+		},
+		{
+			// 공지는 번호 칸이 숫자가 아니라서 pageNum이 0이 됩니다.
+			fixture: "notices.html",
+			want: []pageInformation{
+				{pageNum: 0, numRaw: "공지", title: "게시판 이용 규칙", user: "운영자", view: 98765, comments: 40, date: "2024-05-01", link: fixtureBoardURL + "/1", category: "공지"},
+				{pageNum: 0, numRaw: "공지", title: "이벤트 안내", user: "운영자", view: 5432, date: "2024-05-01", link: fixtureBoardURL + "/2"},
+				{pageNum: 30, title: "첫 글입니다", user: "모그리", view: 10, date: "2024-05-01", link: fixtureBoardURL + "/30"},
+				{pageNum: 29, title: "두 번째 글", user: "초코보", view: 20, date: "2024-05-01", link: fixtureBoardURL + "/29"},
+			},
+		},
+		{
+			// 완전히 삭제된 글의 번호는 건너뛰고, 자리만 남은 글은 deleted로 표시됩니다.
+			fixture: "gaps.html",
+			want: []pageInformation{
+				{pageNum: 95, title: "살아남은 글 1", user: "모그리", view: 1, date: "2024-05-10T10:30:00+09:00", link: fixtureBoardURL + "/95"},
+				{pageNum: 94, title: "삭제된 게시물입니다.", date: "2024-05-01", link: fixtureBoardURL + "/94", deleted: true},
+				{pageNum: 93, title: "살아남은 글 2", user: "초코보", view: 2, date: "2024-05-10T11:55:00+09:00", link: fixtureBoardURL + "/93"},
+				{pageNum: 90, title: "살아남은 글 3", user: "라라펠", view: 3, date: "2024-05-09", link: fixtureBoardURL + "/90"},
+			},
+		},
+		{
+			// no-result 안내문은 경고 없이 건너뜁니다.
+			fixture: "empty.html",
+			want:    []pageInformation{},
+		},
+		{
+			// 조회수가 숫자가 아니면 0이 되고, 제목 칸이 비어 있는 행은 건너뛰고 경고를 남깁니다.
+			fixture: "malformed.html",
+			want: []pageInformation{
+				{pageNum: 12, title: "조회수가 없는 글", user: "모그리", view: 0, date: "날짜 없음", link: fixtureBoardURL + "/12"},
+			},
+			warnings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			server := newFixtureServer(t, map[string]string{"1": tt.fixture})
+			s := newFixtureScraper(server)
+
+			got, warnings, err := s.getPageTitle(context.Background(), s.PageURL(1), false)
+			if err != nil {
+				t.Fatalf("getPageTitle: %v", err)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("getPageTitle(%s) returned %d warnings, want %d: %v", tt.fixture, len(warnings), tt.warnings, warnings)
+			}
+			for i := range tt.want {
+				tt.want[i].row = i
+				tt.want[i].thumbnail = strings.Replace(tt.want[i].thumbnail, "{server}", server.URL, 1)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getPageTitle(%s)\n got: %+v\nwant: %+v", tt.fixture, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPageTitleBlocked(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{"1": "blocked.html"})
+	s := newFixtureScraper(server)
+
+	_, _, err := s.getPageTitle(context.Background(), s.PageURL(1), true)
+	if !isBlocked(err) {
+		t.Fatalf("getPageTitle(blocked.html) error = %v, want a blockedError", err)
+	}
+
+	// 규칙을 모두 비우면 확인하지 않습니다.
+	s = NewScraper(WithBaseURL(server.URL+"/board/ff14/4337?p="), WithHTTPClient(server.Client()), WithBlockRules(nil, nil, nil))
+	if _, _, err := s.getPageTitle(context.Background(), s.PageURL(1), false); err != nil {
+		t.Errorf("getPageTitle without block rules: %v", err)
+	}
+}
+
+func TestGetPageTitleNotHTML(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"error": "rate limited"}`))
+	}))
+	defer server.Close()
+	s := newFixtureScraper(server)
+
+	_, _, err := s.getPageTitle(context.Background(), s.PageURL(1), true)
+	var nonHTML *nonHTMLError
+	if !errors.As(err, &nonHTML) {
+		t.Fatalf("getPageTitle(JSON response) error = %v, want a nonHTMLError", err)
+	}
+	if requests != 1 {
+		t.Errorf("getPageTitle sent %d requests, want 1 (no retry for non-HTML responses)", requests)
+	}
+}
+
+// 재시도한 횟수와 받은 status를 기록하고, 503이면 두 번까지만 다시 요청하는 RetryPolicy
+type recordingRetryPolicy struct {
+	attempts []int
+	statuses []int
+}
+
+func (p *recordingRetryPolicy) NextDelay(attempt int, err error, resp *http.Response) (time.Duration, bool) {
+	p.attempts = append(p.attempts, attempt)
+	if resp == nil {
+		return 0, false
+	}
+	p.statuses = append(p.statuses, resp.StatusCode)
+	return time.Millisecond, resp.StatusCode == http.StatusServiceUnavailable && attempt <= 2
+}
+
+func TestRetryPolicy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	policy := &recordingRetryPolicy{}
+	s := newFixtureScraper(server)
+	WithRetryPolicy(policy)(s)
+
+	_, _, err := s.getPageTitle(context.Background(), s.PageURL(1), true)
+	var status *statusError
+	if !errors.As(err, &status) || status.res.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("getPageTitle(503) error = %v, want a statusError with 503", err)
+	}
+	if requests != 3 {
+		t.Errorf("getPageTitle sent %d requests, want 3 (the request and 2 retries)", requests)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(policy.attempts, want) {
+		t.Errorf("NextDelay attempts = %v, want %v", policy.attempts, want)
+	}
+	if want := []int{503, 503, 503}; !reflect.DeepEqual(policy.statuses, want) {
+		t.Errorf("NextDelay statuses = %v, want %v", policy.statuses, want)
+	}
+}
+
+// -no-redirects면 3xx를 따라가지 않고 재시도 없이 실패해야 하고, 따라갈 때는 -max-redirects까지만 따라갑니다.
+func TestRedirects(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Redirect(w, r, "/member/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	s := NewScraper(WithBaseURL(server.URL+"/board?p="), WithHTTPClient(server.Client()), WithRedirects(defaultMaxRedirects, false))
+	_, _, err := s.getPageTitle(context.Background(), s.PageURL(1), true)
+	var redirect *redirectError
+	if !errors.As(err, &redirect) || redirect.location != server.URL+"/member/elsewhere" {
+		t.Fatalf("getPageTitle with -no-redirects: err = %v, want a redirectError to /member/elsewhere", err)
+	}
+	if requests != 1 {
+		t.Errorf("getPageTitle sent %d requests, want 1 (no retry for redirects)", requests)
+	}
+
+	requests = 0
+	s = NewScraper(WithBaseURL(server.URL+"/board?p="), WithHTTPClient(server.Client()), WithRedirects(2, true))
+	if _, _, err := s.getPageTitle(context.Background(), s.PageURL(1), false); !errors.As(err, &redirect) {
+		t.Fatalf("getPageTitle with -max-redirects 2: err = %v, want a redirectError", err)
+	}
+	if requests != 3 {
+		t.Errorf("getPageTitle sent %d requests, want 3 (the request and 2 redirects)", requests)
+	}
+}
+
+func TestExtraFields(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{"1": "normal.html"})
+	s := newFixtureScraper(server)
+	fields := []extraField{}
+	for _, spec := range []string{"reco=td.reco", "href=td.tit div div a@href"} {
+		f, err := parseExtraField(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields = append(fields, f)
+	}
+	WithExtraFields(fields)(s)
+
+	pages, _, err := s.getPageTitle(context.Background(), s.PageURL(1), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"reco": "5", "href": fixtureBoardURL + "/63"}
+	if got := pages[2].extra; pages[2].pageNum != 63 || !reflect.DeepEqual(got, want) {
+		t.Errorf("post %d extra = %v, want %v", pages[2].pageNum, got, want)
+	}
+
+	if _, err := parseExtraField("reco=td.reco@"); err == nil {
+		t.Error("parseExtraField accepted an empty attr")
+	}
+}
+
+func TestParsePageKeepEmpty(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "malformed.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages, warnings := parsePage(doc, nil, true, fixtureNow)
+	if len(pages) != 2 || len(warnings) != 1 {
+		t.Errorf("parsePage(keepEmpty) = %d rows, %d warnings; want 2 rows, 1 warning (date)", len(pages), len(warnings))
+	}
+}
+
+// 제목 링크에 href가 없는 행도 건너뛰고 경고를 남기며, keepEmpty면 빈 링크로 남깁니다.
+func TestParsePageNoHref(t *testing.T) {
+	html := `<html><body><div class="board-list"><table><tbody><tr>
+		<td class="num"><span>7</span></td>
+		<td class="tit"><div><div><a>링크 없는 글</a></div></div></td>
+	</tr></tbody></table></div></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pages, warnings := parsePage(doc, nil, false, fixtureNow); len(pages) != 0 || len(warnings) != 1 {
+		t.Errorf("parsePage = %d rows, %d warnings; want 0 rows, 1 warning", len(pages), len(warnings))
+	}
+	if pages, _ := parsePage(doc, nil, true, fixtureNow); len(pages) != 1 || pages[0].link != "" || pages[0].pageNum != 7 {
+		t.Errorf("parsePage(keepEmpty) = %+v, want one row numbered 7 with no link", pages)
+	}
+}
+
+// 명령행 > 환경 변수 > 설정 파일 > 기본값 순서로 적용되어야 합니다.
+func TestConfigPrecedence(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Workers = 2 // 설정 파일에서 읽은 값
+	cfg.RPS = 1
+	env := map[string]string{"SCRAPER_WORKERS": "4", "SCRAPER_RPS": "1.5", "SCRAPER_MATCH": "a", "SCRAPER_MIN_ROWS": "10"}
+	lookup := func(name string) (string, bool) {
+		value, exists := env[name]
+		return value, exists
+	}
+	if err := cfg.loadEnv(lookup); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	if err := fs.Parse([]string{"-rps", "3", "-match", "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Workers != 4 || cfg.RPS != 3 || cfg.MinRows != 10 || cfg.From != 1 {
+		t.Errorf("workers %d, rps %v, min-rows %d, from %d; want 4, 3, 10, 1", cfg.Workers, cfg.RPS, cfg.MinRows, cfg.From)
+	}
+	if len(cfg.Match) != 1 || cfg.Match[0] != "b" {
+		t.Errorf("match = %v, want [b]", cfg.Match)
+	}
+
+	env["SCRAPER_WORKERS"] = "many"
+	if err := cfg.loadEnv(lookup); err == nil || !strings.Contains(err.Error(), "SCRAPER_WORKERS") {
+		t.Errorf("loadEnv with SCRAPER_WORKERS=many: err = %v, want an error naming the variable", err)
+	}
+}
+
+func TestPageURL(t *testing.T) {
+	tests := []struct {
+		baseURL      string
+		serverSort   string
+		want         string
+		pageTemplate string
+	}{
+		{"https://www.inven.co.kr/board/ff14/4337?p=", "", "https://www.inven.co.kr/board/ff14/4337?p=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337", "", "https://www.inven.co.kr/board/ff14/4337?p=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?category=질문&p=", "", "https://www.inven.co.kr/board/ff14/4337?category=질문&p=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?page=", "", "https://www.inven.co.kr/board/ff14/4337?page=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?p=7&category=a", "", "https://www.inven.co.kr/board/ff14/4337?category=a&p=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?p=#list", "", "https://www.inven.co.kr/board/ff14/4337?p=3#list", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?p=", "views", "https://www.inven.co.kr/board/ff14/4337?sort=hit&p=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?sort=old&p=", "recommend", "https://www.inven.co.kr/board/ff14/4337?sort=recommend&p=3", ""},
+		{"https://www.inven.co.kr/board/ff14/4337?p=", "recent", "https://www.inven.co.kr/board/ff14/4337?p=3", ""},
+		{"https://example.com/board/", "", "https://example.com/board/page/3", "{base}/page/{n}"},
+		{"https://example.com/board?p=", "", "https://example.com/board?p=3", "{base}?p={n}"},
+		{"https://example.com/board", "views", "https://example.com/board/page/3?sort=hit#list", "{base}/page/{n}#list"},
+		{"https://example.com/board", "views", "https://example.com/board?sort=hit&p=3", "{base}?sort=old&p={n}"},
+	}
+
+	for _, tt := range tests {
+		s := NewScraper(WithBaseURL(tt.baseURL), WithServerSort(tt.serverSort), WithPageTemplate(tt.pageTemplate))
+		if got := s.PageURL(3); got != tt.want {
+			t.Errorf("PageURL(3) with %q, sort %q, template %q = %q, want %q", tt.baseURL, tt.serverSort, tt.pageTemplate, got, tt.want)
+		}
+	}
+}
+
+// -max-pages는 발견한 마지막 page나 -from, -to와 상관없이 수집할 page 수의 상한이고, 더 좁은 범위가 이깁니다.
+func TestPageRangeMaxPages(t *testing.T) {
+	for _, tt := range []struct {
+		from, to, maxPages int
+		last               int
+		want               []int
+	}{
+		{1, 0, 0, 5, []int{1, 2, 3, 4, 5}},
+		{1, 0, 3, 5, []int{1, 2, 3}},
+		{1, 0, 10, 5, []int{1, 2, 3, 4, 5}},
+		{3, 0, 2, 5, []int{3, 4}},
+		{2, 4, 5, 10, []int{2, 3, 4}},
+		{2, 9, 3, 10, []int{2, 3, 4}},
+		{1, 20, 0, 4, []int{1, 2, 3, 4}},
+		{4, 0, 1, 4, []int{4}},
+		{6, 0, 3, 5, []int{}},
+	} {
+		s := NewScraper(WithPageRange(tt.from, tt.to), WithMaxPages(tt.maxPages))
+		if got := s.pageRange(tt.last); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("-from %d -to %d -max-pages %d, last page %d: pageRange = %v, want %v", tt.from, tt.to, tt.maxPages, tt.last, got, tt.want)
+		}
+	}
+}
+
+// cache를 거쳐도 url.ResolveReference로 바로 계산한 결과와 같아야 하고, page마다 query가 달라도 마찬가지입니다.
+func TestResolveURL(t *testing.T) {
+	refs := []string{
+		"//upload3.inven.co.kr/upload/i65.jpg",
+		"/upload/2024/05/01/bbs/i64.jpg",
+		"i63.jpg",
+		"../i62.jpg",
+		"  /upload/i61.jpg ",
+		"https://static.inven.co.kr/i60.jpg",
+		"?p=2",
+		"#list",
+	}
+	bases := []string{
+		"https://www.inven.co.kr/board/ff14/4337?p=1",
+		"https://www.inven.co.kr/board/ff14/4337?p=2",
+		"https://www.inven.co.kr/board/ff14/4337/page/3",
+		"http://m.inven.co.kr/board/ff14/4337?p=1",
+	}
+
+	for round := 0; round < 2; round++ {
+		for _, rawBase := range bases {
+			base, _ := url.Parse(rawBase)
+			for _, ref := range refs {
+				u, _ := url.Parse(strings.TrimSpace(ref))
+				if got, want := resolveURL(base, ref), base.ResolveReference(u).String(); got != want {
+					t.Errorf("round %d: resolveURL(%q, %q) = %q, want %q", round, rawBase, ref, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestURLCacheEviction(t *testing.T) {
+	c := newURLCache(2)
+	c.add("a", "1")
+	c.add("b", "2")
+	c.get("a") // b가 가장 오래 사용하지 않은 항목이 됩니다.
+	c.add("c", "3")
+
+	if _, exists := c.get("b"); exists {
+		t.Error("b should have been evicted")
+	}
+	for key, want := range map[string]string{"a": "1", "c": "3"} {
+		if got, exists := c.get(key); !exists || got != want {
+			t.Errorf("get(%q) = %q, %v; want %q", key, got, exists, want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"10:30", "2024-05-10T10:30:00+09:00"},
+		{"05-01", "2024-05-01"},
+		{"12-31", "2023-12-31"},
+		{"2024.04.30", "2024-04-30"},
+		{"24.04.30", "2024-04-30"},
+		{"2024-04-30 08:15", "2024-04-30T08:15:00+09:00"},
+		{"방금", "2024-05-10T12:00:00+09:00"},
+		{"30초 전", "2024-05-10T11:59:30+09:00"},
+		{"5분 전", "2024-05-10T11:55:00+09:00"},
+		{"1시간 전", "2024-05-10T11:00:00+09:00"},
+		{"3일 전", "2024-05-07"},
+		{"어제", "2024-05-09"},
+		{"그제", "2024-05-08"},
+		{"어제 23:10", "2024-05-09T23:10:00+09:00"},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseDate(tt.raw, fixtureNow)
+		if !ok || got != tt.want {
+			t.Errorf("parseDate(%q) = %q, %v; want %q", tt.raw, got, ok, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"날짜 없음", "25:00", "어제 밤", "13-45"} {
+		if got, ok := parseDate(raw, fixtureNow); ok {
+			t.Errorf("parseDate(%q) = %q, want it to be unrecognized", raw, got)
+		}
+	}
+}
+
+func TestSplitCategory(t *testing.T) {
+	tests := []struct {
+		title, category, rest string
+	}{
+		{"[질문] 템 세팅 질문드립니다", "질문", "템 세팅 질문드립니다"},
+		{"【정보】패치 노트", "정보", "패치 노트"},
+		{"말머리 없는 글 [1]", "", "말머리 없는 글 [1]"},
+		{"[] 빈 말머리", "", "[] 빈 말머리"},
+		{"[닫히지 않은 괄호", "", "[닫히지 않은 괄호"},
+	}
+
+	for _, tt := range tests {
+		category, rest := splitCategory(tt.title)
+		if category != tt.category || rest != tt.rest {
+			t.Errorf("splitCategory(%q) = %q, %q; want %q, %q", tt.title, category, rest, tt.category, tt.rest)
+		}
+	}
+}
+
+func TestSortAndDedupPages(t *testing.T) {
+	// 같은 데이터를 goroutine이 끝나는 순서만 다르게 받아도 결과가 같아야 합니다.
+	pages := []pageInformation{
+		{pageNum: 10, title: "b", listPage: 2, row: 0},
+		{pageNum: 0, title: "공지", link: "/1", listPage: 2, row: 0},
+		{pageNum: 10, title: "b", listPage: 1, row: 29},
+		{pageNum: 0, title: "공지", link: "/1", listPage: 1, row: 0},
+		{pageNum: 9, title: "a", listPage: 2, row: 1},
+	}
+	reversed := make([]pageInformation, len(pages))
+	for i := range pages {
+		reversed[len(pages)-1-i] = pages[i]
+	}
+
+	sortPages(pages)
+	sortPages(reversed)
+	if !reflect.DeepEqual(pages, reversed) {
+		t.Fatalf("sortPages depends on input order:\n%+v\n%+v", pages, reversed)
+	}
+
+	got := dedupPages(pages, nil)
+	want := []pageInformation{
+		{pageNum: 0, title: "공지", link: "/1", listPage: 1, row: 0},
+		{pageNum: 9, title: "a", listPage: 2, row: 1},
+		{pageNum: 10, title: "b", listPage: 1, row: 29},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupPages\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestCompareUsers(t *testing.T) {
+	before := []pageInformation{
+		{user: "모그리", view: 10},
+		{user: "bob", view: 5},
+	}
+	after := []pageInformation{
+		{user: "모그리", view: 7},
+		{user: "모그리", view: 1},
+		{user: "새사람", view: 100},
+	}
+
+	got := compareUsers(before, after)
+	want := []userDelta{
+		{user: "새사람", postsAfter: 1, viewsAfter: 100},
+		{user: "bob", postsBefore: 1, viewsBefore: 5},
+		{user: "모그리", postsBefore: 1, postsAfter: 2, viewsBefore: 10, viewsAfter: 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("compareUsers\n got: %+v\nwant: %+v", got, want)
+	}
+	for i, status := range []string{"new", "silent", ""} {
+		if got[i].status() != status {
+			t.Errorf("%s: status() = %q, want %q", got[i].user, got[i].status(), status)
+		}
+	}
+}
+
+func TestRateLimiterWaitCancel(t *testing.T) {
+	// 0.1 rps면 두 번째 요청은 10초를 기다려야 하지만, 취소하면 바로 깨어나야 합니다.
+	l := &rateLimiter{}
+	l.SetRate(0.1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := l.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait after cancel = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Wait returned %v after cancel, want it to wake up immediately", elapsed)
+	}
+}
+
+func TestGetPages(t *testing.T) {
+	// 첫 page에 글이 3개이고 첫 글 번호가 65라서 65/3+1 = 22 page로 짐작하지만, 실제로는 2가 마지막 page입니다.
+	server := newFixtureServer(t, map[string]string{
+		"":  "normal.html",
+		"1": "normal.html",
+		"2": "gaps.html",
+	})
+	s := newFixtureScraper(server)
+
+	if got, _ := s.getPages(context.Background()); got != 2 {
+		t.Errorf("getPages() = %d, want 2", got)
+	}
+}
+
+// 첫 글 번호가 "1,234"처럼 쉼표로 나와도 parsePage와 같이 읽어서 마지막 page를 찾습니다.
+func TestGetPagesCommaNumber(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{
+		"":  "commas.html",
+		"1": "commas.html",
+		"2": "gaps.html",
+	})
+	s := newFixtureScraper(server)
+
+	got, err := s.getPages(context.Background())
+	if err != nil || got != 2 {
+		t.Errorf("getPages() = %d, %v; want 2", got, err)
+	}
+}
+
+// 추천글 목록은 1, 2, 4, 8 page를 확인한 뒤 4~8 사이를 이분 탐색해서 마지막 page를 찾습니다.
+func TestGetPagesWidens(t *testing.T) {
+	// -posts-per-page 30으로 짐작한 마지막 page는 3이지만, page마다 글이 적어서 실제로는 5 page까지 있습니다.
+	server := newFixtureServer(t, map[string]string{
+		"":  "normal.html",
+		"1": "normal.html",
+		"2": "normal.html",
+		"3": "normal.html",
+		"4": "normal.html",
+		"5": "gaps.html",
+	})
+	s := newFixtureScraper(server)
+	WithPostsPerPage(30)(s)
+
+	if got, _ := s.getPages(context.Background()); got != 5 {
+		t.Errorf("getPages() = %d, want 5", got)
+	}
+}
+
+// 마지막 page를 찾다가 차단된 page가 나오면 계속 찾지 않고 blockedError를 돌려줍니다.
+func TestGetPagesBlocked(t *testing.T) {
+	for _, mode := range []string{"normal", "recommended"} {
+		// 일반 목록은 짐작한 마지막 page(22)의 다음 page부터, 추천글 목록은 2 page부터 확인합니다.
+		server := newFixtureServer(t, map[string]string{
+			"":   "normal.html",
+			"1":  "normal.html",
+			"2":  "blocked.html",
+			"23": "blocked.html",
+		})
+		s := newFixtureScraper(server)
+		WithMode(mode)(s)
+
+		got, err := s.getPages(context.Background())
+		if !errors.Is(err, ErrBlocked) {
+			t.Errorf("%s: getPages() = %d, %v; want ErrBlocked", mode, got, err)
+		}
+	}
+}
+
+func TestGetPagesRecommended(t *testing.T) {
+	pages := map[string]string{}
+	for p := 1; p <= 5; p++ {
+		pages[fmt.Sprint(p)] = "normal.html"
+	}
+	server := newFixtureServer(t, pages)
+	s := newFixtureScraper(server)
+	WithMode("recommended")(s)
+
+	if got, _ := s.getPages(context.Background()); got != 5 {
+		t.Errorf("getPages() in recommended mode = %d, want 5", got)
+	}
+	if got, want := s.PageURL(3), server.URL+"/board/ff14/4337?my=chu&p=3"; got != want {
+		t.Errorf("PageURL(3) = %q, want %q", got, want)
+	}
+}
+
+func TestScrape(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{
+		"":  "normal.html",
+		"1": "normal.html",
+		"2": "gaps.html",
+	})
+	s := newFixtureScraper(server)
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+
+	wantNums := []int{63, 64, 65, 90, 93, 95}
+	gotNums := []int{}
+	for _, page := range got {
+		gotNums = append(gotNums, page.pageNum)
+	}
+	if !reflect.DeepEqual(gotNums, wantNums) {
+		t.Errorf("Scrape() post numbers = %v, want %v", gotNums, wantNums)
+	}
+}
+
+// 네트워크 없이 p 쿼리 값에 맞는 testdata 파일을 바로 파싱해서 돌려주는 Fetcher
+type fixtureFetcher map[string]string
+
+func (f fixtureFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	name, exists := f[u.Query().Get("p")]
+	if !exists {
+		name = "empty.html"
+	}
+
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return goquery.NewDocumentFromReader(file)
+}
+
+func TestScrapeWithFetcher(t *testing.T) {
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		WithFetcher(fixtureFetcher{"": "normal.html", "1": "normal.html", "2": "gaps.html"}),
+	)
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) != 6 {
+		t.Errorf("Scrape() returned %d posts, want 6", len(got))
+	}
+}
+
+// 요청한 URL을 기록하는 Fetcher
+type recordingFetcher struct {
+	fixtureFetcher
+	mu   *sync.Mutex
+	urls *[]string
+}
+
+func (f recordingFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	f.mu.Lock()
+	*f.urls = append(*f.urls, rawURL)
+	f.mu.Unlock()
+	return f.fixtureFetcher.Fetch(ctx, rawURL)
+}
+
+func TestScrapeFirstPageOnly(t *testing.T) {
+	pages := fixtureFetcher{"": "full.html"}
+	for p := 1; p <= 32; p++ {
+		pages[strconv.Itoa(p)] = "full.html"
+	}
+	var urls []string
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		WithFetcher(recordingFetcher{pages, &sync.Mutex{}, &urls}),
+		WithWorkers(4),
+		WithFirstPageOnly(true),
+	)
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(urls) != 1 || urls[0] != s.PageURL(1) {
+		t.Errorf("requested %v, want only %s", urls, s.PageURL(1))
+	}
+	if len(got) == 0 {
+		t.Fatal("Scrape returned no posts")
+	}
+	for _, page := range got {
+		if page.listPage != 1 {
+			t.Errorf("post %d came from page %d, want 1", page.pageNum, page.listPage)
+		}
+	}
+}
+
+// 파싱 중에 panic이 나면 그 page만 실패로 기록하고 나머지 page는 계속 수집해야 합니다.
+// 마지막 page를 찾는 첫 요청 뒤로는, p=2 요청이 잠깐 기다린 뒤 항상 재시도할 만한 에러로 실패하는 Fetcher
+type flakyFetcher struct {
+	fixtureFetcher
+	requests *atomic.Int32
+}
+
+func (f flakyFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	if strings.HasSuffix(rawURL, "p=2") && f.requests.Add(1) > 1 {
+		select {
+		case <-time.After(10 * time.Millisecond):
+			return nil, errors.New("connection reset")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return f.fixtureFetcher.Fetch(ctx, rawURL)
+}
+
+func TestScrapePageTimeout(t *testing.T) {
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithFetcher(flakyFetcher{fixtureFetcher{"": "normal.html", "1": "normal.html", "2": "gaps.html"}, new(atomic.Int32)}),
+		WithPageTimeout(50*time.Millisecond),
+	)
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("Scrape() returned %d posts, want the 3 posts of page 1", len(got))
+	}
+	if failed := s.Failed(); len(failed) != 1 || failed[0] != 2 {
+		t.Errorf("Failed() = %v, want [2]", failed)
+	}
+}
+
+func TestScrapePanicIsolated(t *testing.T) {
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithFetcher(fixtureFetcher{"": "normal.html", "1": "normal.html", "2": "gaps.html"}),
+	)
+	s.parse = func(doc *goquery.Document, base *url.URL, keepEmpty bool, now time.Time) ([]pageInformation, []parseWarning) {
+		if base.Query().Get("p") == "2" {
+			var row *goquery.Selection
+			row.Text() // nil pointer dereference
+		}
+		return parsePage(doc, base, keepEmpty, now)
+	}
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("Scrape() returned %d posts, want the 3 posts of page 1", len(got))
+	}
+	if failed := s.Failed(); len(failed) != 1 || failed[0] != 2 {
+		t.Errorf("Failed() = %v, want [2]", failed)
+	}
+}
+
+// Fetcher를 바꾸면 http.Client로는 요청하지 않아야 합니다.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("unexpected request to %s", req.URL)
+}
+
+// 30개 글과 공지 2개가 있는 목록 page 하나를 파싱하는 비용을 측정합니다.
+// 예전에는 제목을 뽑을 때 행마다 td.tit의 a를 Clone()해서 자식(카테고리, 댓글 수)을 지우고
+// 행 목록 전체도 Clone()했지만, 지금은 ownText로 직속 text node만 읽습니다.
+func BenchmarkParsePage(b *testing.B) {
+	body, err := os.ReadFile(filepath.Join("testdata", "full.html"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if pages, _ := parsePage(doc, nil, false, fixtureNow); len(pages) != 32 {
+			b.Fatalf("parsePage returned %d rows, want 32", len(pages))
+		}
+	}
+}
+
+func TestReadBoardURLs(t *testing.T) {
+	input := "https://www.inven.co.kr/board/ff14/4337?p=\n\n# comment\n  https://www.inven.co.kr/board/lostark/4811?p=  \n"
+	boards, err := readBoardURLs(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://www.inven.co.kr/board/ff14/4337?p=", "https://www.inven.co.kr/board/lostark/4811?p="}
+	if !reflect.DeepEqual(boards, want) {
+		t.Errorf("boards = %q, want %q", boards, want)
+	}
+	if got := boardName(boards[0]); got != "www-inven-co-kr-board-ff14-4337" {
+		t.Errorf("boardName(%q) = %q", boards[0], got)
+	}
+
+	for _, input := range []string{"", "# only a comment\n", "https://www.inven.co.kr/board/ff14/4337?p=\nboard/ff14\n"} {
+		if _, err := readBoardURLs(strings.NewReader(input)); err == nil {
+			t.Errorf("readBoardURLs(%q) returned no error", input)
+		}
+	}
+}
+
+func TestPostGaps(t *testing.T) {
+	pages := []pageInformation{{pageNum: 0}, {pageNum: 110}, {pageNum: 104}, {pageNum: 109}, {pageNum: 101}, {pageNum: 105}, {pageNum: 101}}
+	gaps, lo, hi := postGaps(pages)
+	want := []seenSpan{{102, 103}, {106, 108}}
+	if !reflect.DeepEqual(gaps, want) || lo != 101 || hi != 110 {
+		t.Errorf("postGaps() = %v, %d, %d, want %v, 101, 110", gaps, lo, hi, want)
+	}
+	if got := gapCount(gaps); got != 5 {
+		t.Errorf("gapCount() = %d, want 5", got)
+	}
+}
+
+func TestMergeExports(t *testing.T) {
+	older := []pageInformation{{pageNum: 2, title: "a", view: 10}, {pageNum: 1, title: "b", view: 5}, {title: "notice", link: "/n", view: 1}}
+	newer := []pageInformation{{pageNum: 2, title: "a (edited)", view: 8}, {pageNum: 3, title: "c", view: 1}, {title: "notice", link: "/n", view: 2}}
+
+	views := mergeExports([][]pageInformation{older, newer}, "views", nil)
+	wantViews := []string{"notice", "b", "a", "c"}
+	latest := mergeExports([][]pageInformation{older, newer}, "latest", nil)
+	wantLatest := []string{"notice", "b", "a (edited)", "c"}
+	for _, tc := range []struct {
+		keep string
+		got  []pageInformation
+		want []string
+	}{{"views", views, wantViews}, {"latest", latest, wantLatest}} {
+		titles := []string{}
+		for _, page := range tc.got {
+			titles = append(titles, page.title)
+		}
+		if !reflect.DeepEqual(titles, tc.want) {
+			t.Errorf("mergeExports(%s) titles = %q, want %q", tc.keep, titles, tc.want)
+		}
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	pages := []pageInformation{
+		{pageNum: 1, title: "첫 글, \"인용\"\n두 줄", user: "a", link: "https://www.inven.co.kr/board/ff14/4337/1"},
+		{pageNum: 2, title: "둘째 글", user: "b", view: 3, link: "https://www.inven.co.kr/board/ff14/4337/2"},
+	}
+	cfg := defaultConfig()
+	for _, format := range []string{"csv", "json", "ndjson"} {
+		path := filepath.Join(t.TempDir(), "pages."+format)
+		if err := writeOutput(path, format, pages, cfg); err != nil {
+			t.Fatal(err)
+		}
+		if err := validateOutput(path, format, pages, cfg); err != nil {
+			t.Errorf("%s: validateOutput: %v", format, err)
+		}
+
+		changed := append([]pageInformation{}, pages...)
+		changed[1].title = "다른 글"
+		if err := validateOutput(path, format, changed, cfg); err == nil {
+			t.Errorf("%s: validateOutput did not notice a changed title", format)
+		}
+		if err := validateOutput(path, format, pages[:1], cfg); err == nil {
+			t.Errorf("%s: validateOutput did not notice a missing row", format)
+		}
+	}
+}
+
+// -strip-newlines는 값 안의 줄바꿈을 공백으로 바꾸고, -crlf는 행 끝(과 따옴표 안의 줄바꿈)을 \r\n으로 씁니다.
+func TestWriteCSVNewlines(t *testing.T) {
+	pages := []pageInformation{
+		{pageNum: 1, title: "첫 줄\n둘째 줄"},
+		{pageNum: 2, title: "a\r\nb"},
+		{pageNum: 3, title: "c\rd"},
+	}
+	for _, tt := range []struct {
+		strip, crlf bool
+		want        string
+	}{
+		{false, false, "No.,Title\n1,\"첫 줄\n둘째 줄\"\n2,\"a\r\nb\"\n3,\"c\rd\"\n"},
+		{false, true, "No.,Title\r\n1,\"첫 줄\r\n둘째 줄\"\r\n2,\"a\r\nb\"\r\n3,\"cd\"\r\n"},
+		{true, false, "No.,Title\n1,첫 줄 둘째 줄\n2,a b\n3,c d\n"},
+		{true, true, "No.,Title\r\n1,첫 줄 둘째 줄\r\n2,a b\r\n3,c d\r\n"},
+	} {
+		cfg := defaultConfig()
+		cfg.Fields = stringList{"num", "title"}
+		cfg.StripNewlines = tt.strip
+		cfg.CRLF = tt.crlf
+
+		var b bytes.Buffer
+		if err := writeCSV(&b, pages, cfg); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("-strip-newlines=%v -crlf=%v:\n got: %q\nwant: %q", tt.strip, tt.crlf, got, tt.want)
+		}
+	}
+}
+
+// file sink는 -sink-flush-records만큼 쌓였을 때 파일에 씁니다.
+func TestFileSinkFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "posts.ndjson")
+	sink, err := openFileSink(path, sinkFlush{records: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := func() int {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(data), "\n")
+	}
+
+	ctx := context.Background()
+	pages := []pageInformation{{pageNum: 2, title: "둘째 글"}, {pageNum: 1, title: "첫 글"}}
+	if err := sink.Publish(ctx, pages); err != nil {
+		t.Fatal(err)
+	}
+	if n := lines(); n != 0 {
+		t.Errorf("after 2 posts the file has %d lines, want 0", n)
+	}
+	if err := sink.Publish(ctx, pages); err != nil {
+		t.Fatal(err)
+	}
+	if n := lines(); n != 3 {
+		t.Errorf("after 4 posts the file has %d lines, want 3", n)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := lines(); n != 4 {
+		t.Errorf("after Close the file has %d lines, want 4", n)
+	}
+}
+
+// streaming merge는 메모리에서 합친 결과와 같은 파일을 써야 합니다.
+func TestStreamMergeExports(t *testing.T) {
+	dir := t.TempDir()
+	older := []pageInformation{
+		{title: "공지", link: "/n1", view: 1},
+		{pageNum: 1, title: "b", user: "x", view: 5, link: "/1"},
+		{pageNum: 2, title: "a", user: "y", view: 10, link: "/2"},
+	}
+	newer := []pageInformation{
+		{title: "공지", link: "/n1", view: 2},
+		{title: "새 공지", link: "/n2"},
+		{pageNum: 2, title: "a (수정)", user: "y", view: 8, link: "/2"},
+		{pageNum: 3, title: "c, \"d\"", user: "z", view: 1, link: "/3"},
+	}
+	paths := []string{filepath.Join(dir, "older.csv"), filepath.Join(dir, "newer.ndjson")}
+	cfg := defaultConfig()
+	if err := writeOutput(paths[0], "csv", older, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeOutput(paths[1], "ndjson", newer, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// num+title로 구분하면 제목을 고친 page 2는 두 행으로 남습니다.
+	for _, dedupKey := range []string{"", "num+title"} {
+		for _, keep := range mergeKeepModes {
+			for _, format := range []string{"csv", "json", "ndjson"} {
+				for _, compact := range []bool{false, true} {
+					cfg := defaultConfig()
+					cfg.Format, cfg.Compact, cfg.DedupKey = format, compact, dedupKey
+					cfg.Output = filepath.Join(dir, "stream."+format)
+					if _, _, _, err := streamMerge(paths, keep, cfg); err != nil {
+						t.Fatalf("streamMerge(%s, %s): %v", keep, format, err)
+					}
+
+					exports := [][]pageInformation{}
+					for _, path := range paths {
+						pages, err := readExport(path, "")
+						if err != nil {
+							t.Fatal(err)
+						}
+						exports = append(exports, pages)
+					}
+					merged := mergeExports(exports, keep, cfg.dedupFields())
+					if want := map[string]int{"": 5, "num+title": 6}[dedupKey]; len(merged) != want {
+						t.Errorf("mergeExports(%s, -dedup-key %q) = %d posts, want %d", keep, dedupKey, len(merged), want)
+					}
+					want := filepath.Join(dir, "memory."+format)
+					if err := writeOutput(want, format, merged, cfg); err != nil {
+						t.Fatal(err)
+					}
+
+					got, _ := os.ReadFile(cfg.Output)
+					wantData, _ := os.ReadFile(want)
+					if !bytes.Equal(got, wantData) {
+						t.Errorf("streamMerge(%s, %s, compact %v, -dedup-key %q) =\n%s\nwant\n%s", keep, format, compact, dedupKey, got, wantData)
+					}
+				}
+			}
+		}
+	}
+
+	// num이 없는 -dedup-key는 같은 게시글이 이어서 나오지 않으므로 streaming merge를 쓰지 않습니다.
+	for key, want := range map[string]bool{"": true, "num": true, "num+category": true, "link": false, "title+user": false} {
+		cfg := defaultConfig()
+		cfg.DedupKey = key
+		if got := cfg.streamableMerge(); got != want {
+			t.Errorf("-dedup-key %q: streamableMerge() = %v, want %v", key, got, want)
+		}
+	}
+
+	unsorted := filepath.Join(dir, "unsorted.csv")
+	if err := writeOutput(unsorted, "csv", []pageInformation{older[2], older[1]}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Output = filepath.Join(dir, "unsorted-out.csv")
+	var unsortedErr *unsortedExportError
+	if _, _, _, err := streamMerge([]string{unsorted}, "views", cfg); !errors.As(err, &unsortedErr) {
+		t.Errorf("streamMerge(unsorted) error = %v, want an unsortedExportError", err)
+	}
+}
+
+// -match는 하나라도 포함된 글만 남기고, -exclude는 하나라도 포함된 글을 지웁니다. 둘 다 걸리면 exclude가 이깁니다.
+func TestKeywordFilter(t *testing.T) {
+	pages := []pageInformation{
+		{pageNum: 1, title: "레이드 공략 정리"},
+		{pageNum: 2, title: "[판매] 레이드 장비"},
+		{pageNum: 3, title: "Patch Notes 7.0"},
+		{pageNum: 4, title: "잡담입니다"},
+	}
+	for _, tt := range []struct {
+		match, exclude []string
+		regex          bool
+		want           []int
+	}{
+		{nil, nil, false, []int{1, 2, 3, 4}},
+		{[]string{"레이드"}, nil, false, []int{1, 2}},
+		{[]string{"patch"}, nil, false, []int{3}},
+		{[]string{"레이드", "잡담"}, nil, false, []int{1, 2, 4}},
+		{nil, []string{"[판매]"}, false, []int{1, 3, 4}},
+		{nil, []string{"NOTES", "잡담"}, false, []int{1, 2}},
+		{[]string{"레이드"}, []string{"판매"}, false, []int{1}},
+		{[]string{"레이드"}, []string{"레이드"}, false, []int{}},
+		{[]string{`^\[판매\]`}, nil, true, []int{2}},
+		{[]string{`\d+\.\d+`}, nil, true, []int{3}},
+		{nil, []string{`^\[.*\]`, "patch"}, true, []int{1, 4}},
+		// -regex가 아니면 [판매]는 글자 그대로입니다.
+		{[]string{"^[판매]"}, nil, false, []int{}},
+	} {
+		filter, err := keywordFilter(tt.match, tt.exclude, tt.regex)
+		if err != nil {
+			t.Fatalf("keywordFilter(%q, %q, %v): %v", tt.match, tt.exclude, tt.regex, err)
+		}
+		kept, _ := filter(append([]pageInformation{}, pages...))
+		got := []int{}
+		for _, page := range kept {
+			got = append(got, page.pageNum)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("-match %q -exclude %q -regex=%v: kept %v, want %v", tt.match, tt.exclude, tt.regex, got, tt.want)
+		}
+	}
+
+	if _, err := keywordFilter(nil, []string{"("}, true); err == nil {
+		t.Error("keywordFilter accepted an invalid -exclude regex")
+	}
+}
+
+func TestRequireFields(t *testing.T) {
+	num, _ := findOutputField("num")
+	fetcher := fixtureFetcher{"": "notices.html", "1": "notices.html"}
+
+	s := NewScraper(WithBaseURL(fixtureBoardURL+"?p="), WithFetcher(fetcher), WithRequiredFields([]outputField{num}))
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Scrape() returned %d posts, want the 2 numbered posts", len(got))
+	}
+	if warnings := s.Warnings(); len(warnings) != 2 || !strings.Contains(warnings[0].reason, "required field num") {
+		t.Errorf("Warnings() = %v, want 2 required field warnings", warnings)
+	}
+
+	s = NewScraper(WithBaseURL(fixtureBoardURL+"?p="), WithFetcher(fetcher), WithRequiredFields([]outputField{num}), WithFailFast(true))
+	var missing *missingFieldError
+	if _, err := s.Scrape(context.Background()); !errors.As(err, &missing) {
+		t.Errorf("Scrape with fail-fast: err = %v, want a missingFieldError", err)
+	}
+}
+
+func TestScrapeOriginalOrder(t *testing.T) {
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithFetcher(fixtureFetcher{"": "notices.html", "1": "normal.html", "2": "notices.html"}),
+		WithPageRange(1, 2),
+		WithWorkers(2),
+		WithOriginalOrder(true),
+	)
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	nums := []int{}
+	for _, page := range got {
+		nums = append(nums, page.pageNum)
+	}
+	// page 1의 순서 그대로, 그 뒤에 page 2의 공지와 게시글이 옵니다.
+	if want := []int{65, 64, 63, 0, 0, 30, 29}; !reflect.DeepEqual(nums, want) {
+		t.Errorf("Scrape() post numbers = %v, want %v", nums, want)
+	}
+}
+
+// 게시글 link로 온 요청에는 본문만 있는 page를 돌려주는 Fetcher
+type postFetcher struct {
+	fixtureFetcher
+	bodies map[string]string // link의 마지막 경로 -> 본문 HTML
+}
+
+func (f postFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	if body, exists := f.bodies[path.Base(rawURL)]; exists {
+		return goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + body + "</body></html>"))
+	}
+	return f.fixtureFetcher.Fetch(ctx, rawURL)
+}
+
+func TestScrapeBodyLength(t *testing.T) {
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		WithFetcher(postFetcher{
+			fixtureFetcher: fixtureFetcher{"": "normal.html", "1": "normal.html"},
+			bodies: map[string]string{
+				"65": "<div id=\"powerbbsContent\"> 안녕하세요\n\n  <b>본문</b>입니다 </div>",
+				"64": `<div id="powerbbsContent"></div>`,
+				"63": `<div class="other">본문 없음</div>`,
+			},
+		}),
+		WithBodyLength(true),
+	)
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	want := map[int]int{65: len([]rune("안녕하세요 본문입니다")), 64: 0, 63: -1}
+	for _, page := range got {
+		if page.bodyLen != want[page.pageNum] {
+			t.Errorf("post %d: bodyLen = %d, want %d", page.pageNum, page.bodyLen, want[page.pageNum])
+		}
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.RequestHeaders = stringList{"x-requested-with: XMLHttpRequest", "Accept-Language: en", "X-Token: a:b"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	s := NewScraper(WithHTTPClient(server.Client()), WithHeaders(cfg.requestHeaders()))
+	res, err := s.get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	want := map[string]string{"X-Requested-With": "XMLHttpRequest", "Accept-Language": "en", "X-Token": "a:b"}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("%s = %q, want %q", name, got.Get(name), value)
+		}
+	}
+
+	for _, bad := range []string{"no colon", ": empty name", "Bad Name: x", "Host: example.com"} {
+		if _, _, err := parseHeader(bad); err == nil {
+			t.Errorf("parseHeader(%q) accepted an invalid header", bad)
+		}
+	}
+}
+
+// p=cancelAt 요청을 받으면 run을 취소하는 Fetcher
+type cancelingFetcher struct {
+	fixtureFetcher
+	cancelAt string
+	cancel   context.CancelFunc
+}
+
+func (f cancelingFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	if strings.HasSuffix(rawURL, "p="+f.cancelAt) {
+		f.cancel()
+		return nil, ctx.Err()
+	}
+	return f.fixtureFetcher.Fetch(ctx, rawURL)
+}
+
+// 중간에 취소되면 남은 page는 요청하지 않고 실패로 남기고, Scrape는 멈추지 않고 ctx의 에러를 리턴해야 합니다.
+func TestScrapeCancel(t *testing.T) {
+	pages := fixtureFetcher{"": "full.html"}
+	for p := 1; p <= 32; p++ {
+		pages[strconv.Itoa(p)] = "full.html"
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		WithFetcher(cancelingFetcher{fixtureFetcher: pages, cancelAt: "5", cancel: cancel}),
+		WithWorkers(4),
+	)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Scrape(ctx)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrPartial) {
+			t.Errorf("Scrape after cancel = %v, want context.Canceled and ErrPartial", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scrape did not return after the run was canceled")
+	}
+
+	if s.Stats().pages+len(s.Failed()) != 32 {
+		t.Errorf("%d pages collected and %d failed, want 32 in total", s.Stats().pages, len(s.Failed()))
+	}
+	if len(s.Failed()) == 0 {
+		t.Error("no pages were recorded as failed after the cancel")
+	}
+}
+
+func TestColumnSchema(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Fields = stringList{"num", "title", "deleted", "body_len"}
+	want := []schemaColumn{{"num", "int64"}, {"title", "string"}, {"deleted", "boolean"}, {"body_len", "int64"}}
+	if got := cfg.columnSchema(); !reflect.DeepEqual(got, want) {
+		t.Errorf("columnSchema() = %v, want %v", got, want)
+	}
+}
+
+func TestScrapeJSONEndpoint(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{"": "normal.html", "1": "normal.html", "json1": "list.json"})
+	s := NewScraper(
+		WithBaseURL(server.URL+"/board/ff14/4337?p="),
+		WithHTTPClient(server.Client()),
+		WithJSONEndpoint("{base}?p=json{n}", "data.list"),
+	)
+	s.now = func() time.Time { return fixtureNow }
+
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Scrape() returned %d posts, want 2: %+v", len(got), got)
+	}
+	post := got[1]
+	if post.pageNum != 65 || post.category != "질문" || post.link != server.URL+"/board/ff14/4337/65" || post.date != "2024-05-01" || post.comments != 3 {
+		t.Errorf("post from the JSON listing = %+v", post)
+	}
+	if got[0].numRaw != "공지" {
+		t.Errorf("notice num_raw = %q, want 공지", got[1].numRaw)
+	}
+	if len(s.Warnings()) != 1 {
+		t.Errorf("got %d warnings, want 1 for the post without a title", len(s.Warnings()))
+	}
+
+	if _, _, err := parseJSONPage([]byte(`{"posts": []}`), "data.list", s.base, false, fixtureNow); err == nil {
+		t.Error("parseJSONPage accepted a response without the list key")
+	}
+}
+
+// 홀수 page는 재시도하지 않는 에러로 실패하는 Fetcher. 마지막 page(32)를 찾는 요청은 성공합니다.
+type oddFailingFetcher struct {
+	fixtureFetcher
+}
+
+func (f oddFailingFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	u, _ := url.Parse(rawURL)
+	if p, _ := strconv.Atoi(u.Query().Get("p")); p%2 == 1 {
+		return nil, &nonHTMLError{url: rawURL, contentType: "text/plain"}
+	}
+	return f.fixtureFetcher.Fetch(ctx, rawURL)
+}
+
+// keep-going은 실패한 page를 모두 모으고, fail-fast는 처음 실패한 page의 에러로 나머지 page를 취소해야 합니다.
+func TestScrapeFailurePolicies(t *testing.T) {
+	pages := fixtureFetcher{"": "full.html"}
+	for p := 1; p <= 32; p++ {
+		pages[strconv.Itoa(p)] = "full.html"
+	}
+	newScraper := func(workers int, failFast bool) *Scraper {
+		return NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(oddFailingFetcher{pages}),
+			WithWorkers(workers),
+			WithFailFast(failFast),
+		)
+	}
+
+	s := newScraper(4, false)
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("keep-going: Scrape() error = %v, want nil", err)
+	}
+	wantFailed := []int{}
+	for p := 1; p <= 32; p += 2 {
+		wantFailed = append(wantFailed, p)
+	}
+	if !reflect.DeepEqual(s.Failed(), wantFailed) {
+		t.Errorf("keep-going: Failed() = %v, want %v", s.Failed(), wantFailed)
+	}
+	for _, page := range got {
+		if page.listPage%2 == 1 {
+			t.Errorf("keep-going: post %d from failed page %d", page.pageNum, page.listPage)
+		}
+	}
+
+	// worker가 하나면 page 1이 먼저 실패하므로 나머지 page는 요청하지 않고 취소됩니다.
+	s = newScraper(1, true)
+	got, err = s.Scrape(context.Background())
+	var nonHTML *nonHTMLError
+	if !errors.As(err, &nonHTML) || !strings.HasPrefix(err.Error(), "page 1:") || !errors.Is(err, ErrPartial) {
+		t.Fatalf("fail-fast: Scrape() error = %v, want page 1's nonHTMLError as ErrPartial", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("fail-fast: Scrape() returned %d posts after the first page failed, want 0", len(got))
+	}
+	if len(s.Failed()) != 32 {
+		t.Errorf("fail-fast: Failed() = %v, want all 32 pages", s.Failed())
+	}
+}
+
+// p 쿼리 값마다 failures에 적힌 횟수만큼 500으로 응답한 뒤부터는 fixture를 돌려주는 서버
+func newFlakyServer(t *testing.T, pages map[string]string, failures map[string]int) *httptest.Server {
+	t.Helper()
+	fixture := newFixtureServer(t, pages)
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Query().Get("p")
+		mu.Lock()
+		fail := failures[p] > 0
+		if fail {
+			failures[p]--
+		}
+		mu.Unlock()
+		if fail {
+			http.Error(w, "temporarily unavailable", http.StatusInternalServerError)
+			return
+		}
+		fixture.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// -retry-failures는 라운드마다 남은 실패한 page만 다시 수집하고, 성공한 page는 Failed()에서 빠져야 합니다.
+func TestScrapeRetryFailures(t *testing.T) {
+	pages := map[string]string{"": "normal.html", "1": "normal.html", "2": "gaps.html"}
+	for _, tt := range []struct {
+		rounds     int
+		wantFailed []int
+		wantPosts  int
+	}{
+		// page 1은 두 번 실패한 뒤에 성공합니다. page 2(gaps.html)의 게시글 3개는 처음부터 수집됩니다.
+		{0, []int{1}, 3},
+		{1, []int{1}, 3},
+		{2, []int{}, 6},
+		{3, []int{}, 6},
+	} {
+		server := newFlakyServer(t, pages, map[string]int{"1": 2})
+		s := newFixtureScraper(server)
+		WithRetryPolicy(defaultRetryPolicy{maxRetries: 0})(s)
+		WithRetryFailures(tt.rounds)(s)
+		s.retryDelay = time.Millisecond
+
+		got, err := s.Scrape(context.Background())
+		if err != nil {
+			t.Fatalf("rounds %d: Scrape: %v", tt.rounds, err)
+		}
+		if failed := s.Failed(); fmt.Sprint(failed) != fmt.Sprint(tt.wantFailed) {
+			t.Errorf("rounds %d: Failed() = %v, want %v", tt.rounds, failed, tt.wantFailed)
+		}
+		if len(got) != tt.wantPosts {
+			t.Errorf("rounds %d: Scrape() returned %d posts, want %d", tt.rounds, len(got), tt.wantPosts)
+		}
+	}
+}
+
+// 실패한 page가 -max-failures-abort를 넘으면 중단하고, 그때까지의 결과를 정렬해서 돌려줘야 합니다.
+func TestScrapeMaxFailures(t *testing.T) {
+	pages := fixtureFetcher{"": "full.html"}
+	for p := 1; p <= 32; p++ {
+		pages[strconv.Itoa(p)] = "full.html"
+	}
+	s := NewScraper(
+		WithBaseURL(fixtureBoardURL+"?p="),
+		WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		WithFetcher(oddFailingFetcher{pages}),
+		WithMaxFailures(3),
+	)
+
+	got, err := s.Scrape(context.Background())
+	if !isTooManyFailures(err) || !errors.Is(err, ErrPartial) {
+		t.Fatalf("Scrape() error = %v, want tooManyFailuresError and ErrPartial", err)
+	}
+	if len(got) == 0 {
+		t.Error("Scrape() returned no partial results")
+	}
+	if !sort.SliceIsSorted(got, func(i, j int) bool { return got[i].pageNum == 0 && got[j].pageNum != 0 }) {
+		t.Error("partial results were not sorted with the notices first")
+	}
+	if len(s.Failed()) <= 3 {
+		t.Errorf("Failed() = %v, want more than 3 pages", s.Failed())
+	}
+}
+
+// 한글을 자모로 나눈(NFD) 목록 page를 돌려주는 Fetcher
+type nfdFetcher struct {
+	fixtureFetcher
+}
+
+func (f nfdFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	doc, err := f.fixtureFetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	html, err := doc.Html()
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(strings.NewReader(norm.NFD.String(html)))
+}
+
+func TestScrapeNormalizeUnicode(t *testing.T) {
+	scrape := func(fetcher Fetcher, normalize bool) []pageInformation {
+		t.Helper()
+		s := NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(fetcher),
+			WithUnicodeNormalization(normalize),
+		)
+		got, err := s.Scrape(context.Background())
+		if err != nil {
+			t.Fatalf("Scrape: %v", err)
+		}
+		return got
+	}
+	pages := fixtureFetcher{"": "normal.html", "1": "normal.html"}
+	want := scrape(pages, false)
+
+	if got := scrape(nfdFetcher{pages}, false); got[0].title == want[0].title {
+		t.Fatalf("fixture title %q is already NFC", got[0].title)
+	}
+	got := scrape(nfdFetcher{pages}, true)
+	for i := range want {
+		if got[i].title != want[i].title || got[i].user != want[i].user {
+			t.Errorf("post %d: got %q by %q, want %q by %q", want[i].pageNum, got[i].title, got[i].user, want[i].title, want[i].user)
+		}
+	}
+}
+
+// 조회수 칸의 class를 바꿔서 viewSelector가 맞지 않는 게시판처럼 만듭니다.
+type renamedViewFetcher struct {
+	fixtureFetcher
+}
+
+func (f renamedViewFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	doc, err := f.fixtureFetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	doc.Find(viewSelector).RemoveClass("view").AddClass("hit")
+	return doc, nil
+}
+
+func TestScrapeMissingView(t *testing.T) {
+	pages := fixtureFetcher{"": "normal.html", "1": "normal.html"}
+	newScraper := func(fetcher Fetcher, action string) *Scraper {
+		return NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(fetcher),
+			WithMissingViewAction(action),
+		)
+	}
+
+	s := newScraper(pages, "warn")
+	if _, err := s.Scrape(context.Background()); err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	for _, w := range s.Warnings() {
+		if w.row == -1 {
+			t.Errorf("unexpected page warning with td.view present: %s", w.reason)
+		}
+	}
+
+	s = newScraper(renamedViewFetcher{pages}, "warn")
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("warn dropped the posts")
+	}
+	warned := false
+	for _, w := range s.Warnings() {
+		warned = warned || (w.row == -1 && w.listPage == 1 && strings.Contains(w.reason, viewSelector))
+	}
+	if !warned {
+		t.Errorf("Warnings() = %+v, want a page warning about %s", s.Warnings(), viewSelector)
+	}
+
+	s = newScraper(renamedViewFetcher{pages}, "skip")
+	if got, err := s.Scrape(context.Background()); err != nil || len(got) != 0 {
+		t.Errorf("Scrape with skip = %d posts, %v; want no posts", len(got), err)
+	}
+	if failed := s.Failed(); len(failed) != 1 || failed[0] != 1 {
+		t.Errorf("Failed() = %v, want [1]", failed)
+	}
+}
+
+// snapshot은 URL과 기준 시간을 gzip header에 담고, 다시 파싱하면 같은 게시글이 나와야 합니다.
+func TestSnapshotHTML(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{"": "normal.html", "1": "normal.html"})
+	dir := t.TempDir()
+	s := newFixtureScraper(server)
+	WithSnapshotDir(dir)(s)
+
+	want, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "1.html.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zr.Comment != s.PageURL(1) || !zr.ModTime.Equal(fixtureNow) {
+		t.Errorf("snapshot header = %q at %v, want %q at %v", zr.Comment, zr.ModTime, s.PageURL(1), fixtureNow)
+	}
+	doc, err := goquery.NewDocumentFromReader(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.parseListing(doc, zr.Comment, zr.ModTime)
+	for i := range want {
+		got[i].listPage, got[i].source = want[i].listPage, want[i].source
+	}
+	sortPages(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("re-parsed snapshot = %+v, want %+v", got, want)
+	}
+}
+
+func TestScrapeFromSnapshots(t *testing.T) {
+	server := newFixtureServer(t, map[string]string{"": "normal.html", "1": "normal.html"})
+	dir := t.TempDir()
+	s := newFixtureScraper(server)
+	WithSnapshotDir(dir)(s)
+	want, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+
+	// 요청하면 실패하는 client로 snapshot만 읽습니다.
+	offline := NewScraper(WithBaseURL(server.URL+"/board/ff14/4337?p="),
+		WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		WithSnapshotSource(dir))
+	offline.now = func() time.Time { return fixtureNow.Add(48 * time.Hour) }
+	got, err := offline.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape from snapshots: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages from snapshots = %+v, want %+v", got, want)
+	}
+
+	if _, err := NewScraper(WithSnapshotSource(t.TempDir())).Scrape(context.Background()); err == nil {
+		t.Error("Scrape from an empty directory succeeded, want an error")
+	}
+}
+
+func TestTableWidth(t *testing.T) {
+	unknown := func() (int, error) { return 0, errors.New("not a tty") }
+	sized := func() (int, error) { return 100, nil }
+	tests := []struct {
+		name       string
+		tableWidth int
+		isTerminal bool
+		size       func() (int, error)
+		columns    string
+		want       int
+	}{
+		{"terminal", 0, true, sized, "", 100},
+		{"unknown size", 0, true, unknown, "", defaultTableWidth},
+		{"unknown size with COLUMNS", 0, true, unknown, "132", 132},
+		{"bad COLUMNS", 0, true, unknown, "wide", defaultTableWidth},
+		{"pipe", 0, false, unknown, "132", 0},
+		{"flag on a pipe", 120, false, unknown, "", 120},
+		{"flag on a terminal", 60, true, sized, "", 60},
+	}
+	for _, tt := range tests {
+		got := tableWidthFor(Config{TableWidth: tt.tableWidth}, tt.isTerminal, tt.size, tt.columns)
+		if got != tt.want {
+			t.Errorf("%s: width = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// 좁은 폭에서도 모든 줄이 폭 안에 들어가야 합니다.
+	var b bytes.Buffer
+	rows := [][]string{{"Num", "Title"}, {"930", strings.Repeat("아주 긴 제목 ", 20)}}
+	writeRows(&b, rows, 40, false)
+	for _, line := range strings.Split(strings.TrimRight(b.String(), "\n"), "\n") {
+		if w := displayWidth(line); w > 40 {
+			t.Errorf("line %q is %d wide, want at most 40", line, w)
+		}
+	}
+}
+
+func TestScrapeErrorKinds(t *testing.T) {
+	scrape := func(ctx context.Context, pages fixtureFetcher) error {
+		t.Helper()
+		s := NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(pages),
+		)
+		_, err := s.Scrape(ctx)
+		return err
+	}
+
+	if err := scrape(context.Background(), fixtureFetcher{"": "blocked.html"}); !errors.Is(err, ErrBlocked) || !isBlocked(err) {
+		t.Errorf("blocked board: err = %v, want ErrBlocked", err)
+	}
+	if err := scrape(context.Background(), fixtureFetcher{"": "empty.html"}); !errors.Is(err, ErrNoPages) {
+		t.Errorf("empty board: err = %v, want ErrNoPages", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	err := scrape(ctx, fixtureFetcher{"": "normal.html", "1": "normal.html"})
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrPartial) {
+		t.Errorf("expired ctx: err = %v, want ErrTimeout only", err)
+	}
+}
+
+func TestMaxPerHost(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "full.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := os.ReadFile(filepath.Join("testdata", "empty.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		if p, _ := strconv.Atoi(r.URL.Query().Get("p")); p <= 32 {
+			w.Write(body)
+		} else {
+			w.Write(empty)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	s := newFixtureScraper(server)
+	WithWorkers(8)(s)
+	WithMaxPerHost(2)(s)
+	got, err := s.Scrape(context.Background())
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("Scrape returned no posts")
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d requests in flight at once, want at most 2", p)
+	}
+}
+
+func TestScrapeBadges(t *testing.T) {
+	scrape := func(selectors map[string]string) map[int]pageInformation {
+		t.Helper()
+		s := NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(fixtureFetcher{"": "badges.html", "1": "badges.html"}),
+			WithBadges(selectors),
+		)
+		got, err := s.Scrape(context.Background())
+		if err != nil {
+			t.Fatalf("Scrape: %v", err)
+		}
+		byNum := map[int]pageInformation{}
+		for _, page := range got {
+			byNum[page.pageNum] = page
+		}
+		return byNum
+	}
+	type badges struct{ new, hot, image bool }
+	check := func(name string, pages map[int]pageInformation, want map[int]badges) {
+		t.Helper()
+		for num, w := range want {
+			p := pages[num]
+			if got := (badges{p.newBadge, p.hot, p.hasImage}); got != w {
+				t.Errorf("%s: post %d badges = %+v, want %+v", name, num, got, w)
+			}
+		}
+	}
+
+	check("defaults", scrape(Config{IncludeBadges: true}.badgeSelectors()), map[int]badges{
+		65: {new: true, image: true},
+		64: {hot: true},
+		63: {image: true},
+	})
+	check("-badge-selector", scrape(Config{IncludeBadges: true, BadgeSelectors: stringList{"hot=td.tit img.icon-fire"}}.badgeSelectors()), map[int]badges{
+		64: {},
+		63: {hot: true, image: true},
+	})
+	check("off", scrape(nil), map[int]badges{65: {}, 64: {}, 63: {}})
+}
+
+func TestIntervalJitter(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"", 0, true},
+		{"20%", 0.2, true},
+		{"0.25", 0.25, true},
+		{"100%", 0, false},
+		{"-5%", 0, false},
+		{"often", 0, false},
+	} {
+		got, err := parseIntervalJitter(tt.value)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseIntervalJitter(%q) = %v, %v; want %v (ok %v)", tt.value, got, err, tt.want, tt.ok)
+		}
+	}
+
+	waits := func(seed int64) []time.Duration {
+		s := NewScraper(WithSeed(seed), WithIntervalJitter(0.2))
+		d := []time.Duration{}
+		for i := 0; i < 20; i++ {
+			d = append(d, s.nextInterval(10*time.Minute))
+		}
+		return d
+	}
+	first := waits(42)
+	if !reflect.DeepEqual(first, waits(42)) {
+		t.Error("the same -seed gave different waits")
+	}
+	varied := false
+	for _, d := range first {
+		if d < 8*time.Minute || d > 12*time.Minute {
+			t.Errorf("wait %v outside 10m ±20%%", d)
+		}
+		varied = varied || d != first[0]
+	}
+	if !varied {
+		t.Errorf("waits %v never changed", first)
+	}
+	if d := NewScraper().nextInterval(10 * time.Minute); d != 10*time.Minute {
+		t.Errorf("wait without jitter = %v, want 10m", d)
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	status := &healthState{}
+	mux := http.NewServeMux()
+	status.register(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		res, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return res.StatusCode, string(body)
+	}
+	expect := func(path string, want int) string {
+		t.Helper()
+		code, body := get(path)
+		if code != want {
+			t.Errorf("GET %s = %d %q, want %d", path, code, body, want)
+		}
+		return body
+	}
+
+	expect("/healthz", http.StatusOK)
+	expect("/readyz", http.StatusServiceUnavailable)
+	expect("/lastrun", http.StatusNotFound)
+
+	// 실패한 실행은 에러와 함께 기록됩니다.
+	status.record(lastRun{StartedAt: fixtureNow, EndedAt: fixtureNow, Error: "fetching page 1: 503 Service Unavailable"})
+	if body := expect("/readyz", http.StatusServiceUnavailable); !strings.Contains(body, "503 Service Unavailable") {
+		t.Errorf("/readyz after a failed run = %q, want the run's error", body)
+	}
+	if body := expect("/lastrun", http.StatusOK); !strings.Contains(body, `"error":"fetching page 1: 503 Service Unavailable"`) {
+		t.Errorf("/lastrun after a failed run = %s", body)
+	}
+
+	status.record(lastRun{StartedAt: fixtureNow, EndedAt: fixtureNow.Add(time.Minute), Rows: 40, FailedPages: 2})
+	expect("/readyz", http.StatusServiceUnavailable)
+	var last lastRun
+	if err := json.Unmarshal([]byte(expect("/lastrun", http.StatusOK)), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Rows != 40 || last.FailedPages != 2 || last.Error != "2 pages failed" || !last.StartedAt.Equal(fixtureNow) {
+		t.Errorf("/lastrun = %+v", last)
+	}
+
+	// 한 번 준비되면 다음 실행에 실패한 page가 있어도 준비된 상태로 남습니다.
+	status.record(lastRun{Rows: 45})
+	expect("/readyz", http.StatusOK)
+	status.record(lastRun{FailedPages: 1})
+	expect("/readyz", http.StatusOK)
+}
+
+func TestParseCount(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"1234", 1234, true},
+		{" 12 ", 12, true},
+		{"1,234", 1234, true},
+		{"1,234,567", 1234567, true},
+		{"[12]", 12, true},
+		{"[ 3 ]", 3, true},
+		{"(7)", 7, true},
+		{"[1,024]", 1024, true},
+		{"1.2k", 1200, true},
+		{"3K", 3000, true},
+		{"1.5만", 15000, true},
+		{"12만", 120000, true},
+		{"2천", 2000, true},
+		{"0", 0, true},
+		{"", 0, true},
+		{"   ", 0, true},
+		{"-", 0, true},
+		{"[-]", 0, true},
+		{"[]", 0, true},
+		{"-5", 0, false},
+		{"+5", 0, false},
+		{"-1.2k", 0, false},
+		{"1.5", 0, false},
+		{"k", 0, false},
+		{"만", 0, false},
+		{"1e3k", 0, false},
+		{"infk", 0, false},
+		{"공지", 0, false},
+		{"12개", 0, false},
+		{"abc", 0, false},
+		{"[12", 0, false},
+		{"99999999999999999999", 0, false},
+	} {
+		got, err := parseCount(tt.text)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseCount(%q) = %d, %v; want %d (ok %v)", tt.text, got, err, tt.want, tt.ok)
+		}
+	}
+
+	html := `<html><body><div class="board-list"><table><tbody><tr>
+		<td class="num"><span>공지</span></td>
+		<td class="tit"><div><div><a href="/b/1">제목<span class="con-comment">[1.2k]</span></a></div></div></td>
+		<td class="view">조회없음</td><td class="reco">-</td>
+	</tr><tr>
+		<td class="num"><span>1.2k</span></td>
+		<td class="tit"><div><div><a href="/b/2">번호가 축약된 행</a></div></div></td>
+		<td class="view">3</td>
+	</tr></tbody></table></div></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, warnings := parsePage(doc, nil, false, fixtureNow)
+	if len(pages) != 2 {
+		t.Fatalf("parsePage = %d rows, want 2", len(pages))
+	}
+	// 게시글 번호는 축약을 풀지 않고 숫자가 아닌 값으로 남깁니다.
+	if page := pages[1]; page.pageNum != 0 || page.numRaw != "1.2k" || page.view != 3 {
+		t.Errorf("abbreviated number row = num %d (%q), view %d; want 0 (\"1.2k\"), 3", page.pageNum, page.numRaw, page.view)
+	}
+	page := pages[0]
+	if page.pageNum != 0 || page.numRaw != "공지" || page.comments != 1200 || page.view != 0 || page.recommend != 0 {
+		t.Errorf("row = num %d (%q), comments %d, view %d, recommend %d; want 0 (\"공지\"), 1200, 0, 0",
+			page.pageNum, page.numRaw, page.comments, page.view, page.recommend)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].reason, "view") {
+		t.Errorf("warnings = %v, want one for the view", warnings)
+	}
+}
+
+func TestParsePostNumber(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"1234", 1234, true},
+		{"1,234", 1234, true},
+		{" 42 ", 42, true},
+		{"1 234", 1234, true},
+		{"1.2k", 0, false},
+		{"3.4만", 0, false},
+		{"공지", 0, false},
+		{"-5", 0, false},
+		{"+5", 0, false},
+		{"", 0, false},
+	} {
+		got, err := parsePostNumber(tt.text)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parsePostNumber(%q) = %d, %v; want %d (ok %v)", tt.text, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDedupKey(t *testing.T) {
+	// 말머리(하위 게시판)마다 번호가 따로 매겨지는 게시판
+	pages := func() []pageInformation {
+		return []pageInformation{
+			{pageNum: 0, title: "공지 1", link: "/n1", category: "공지"},
+			{pageNum: 0, title: "공지 2", link: "/n2", category: "공지"},
+			{pageNum: 5, title: "a", link: "/a/5", category: "a"},
+			{pageNum: 5, title: "b", link: "/b/5", category: "b"},
+			{pageNum: 5, title: "a", link: "/a/5", category: "a", listPage: 2},
+		}
+	}
+
+	for _, tt := range []struct {
+		key  string
+		want []string
+	}{
+		{"", []string{"공지 1", "공지 2", "a"}},
+		{"num+category", []string{"공지 1", "공지 2", "a", "b"}},
+		{"link", []string{"공지 1", "공지 2", "a", "b"}},
+		{"title", []string{"공지 1", "공지 2", "a", "b"}},
+	} {
+		cfg := defaultConfig()
+		cfg.DedupKey = tt.key
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("-dedup-key %q: Validate: %v", tt.key, err)
+		}
+		got := []string{}
+		for _, page := range dedupPages(pages(), cfg.dedupFields()) {
+			got = append(got, page.title)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("-dedup-key %q: kept %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	for _, key := range []string{"num+board", "num+", "+"} {
+		cfg := defaultConfig()
+		cfg.DedupKey = key
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("-dedup-key %q: Validate accepted it", key)
+		}
+	}
+	// 없는 필드를 주면 쓸 수 있는 필드를 알려줍니다.
+	cfg := defaultConfig()
+	cfg.DedupKey = "num+board"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "num, num_raw, title") {
+		t.Errorf("-dedup-key num+board: Validate = %v, want the list of fields", err)
+	}
+	cfg = defaultConfig()
+	cfg.DedupKey = "link"
+	cfg.Dedup = false
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "-dedup-key requires -dedup") {
+		t.Errorf("-dedup-key without -dedup: Validate = %v", err)
+	}
+}
+
+// -throttle-on-error는 실패가 몰리면 요청 간격을 늘리고, 다시 성공하면 원래 간격까지만 되돌려야 합니다.
+func TestThrottleOnError(t *testing.T) {
+	server := newFlakyServer(t, map[string]string{"": "normal.html", "1": "normal.html"}, map[string]int{"1": 6})
+	s := newFixtureScraper(server)
+	WithRateLimit(200)(s)
+	WithThrottleOnError(throttleSettings{threshold: 0.5, window: 4, factor: 0.5, recovery: 2, minRate: 40})(s)
+	s.throttle.start()
+
+	interval := func() time.Duration {
+		return time.Duration(float64(time.Second) / s.limiter.Rate())
+	}
+	get := func() {
+		t.Helper()
+		res, err := s.get(context.Background(), s.PageURL(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	// window(4)의 절반이 찰 때마다 판단하므로 실패 2번마다 간격이 두 배가 되고, minRate 아래로는 줄이지 않습니다.
+	for _, want := range []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+		get()
+		if got := interval(); got != want {
+			t.Errorf("after a failed request: interval = %v, want %v", got, want)
+		}
+	}
+	// 성공할 때마다 recovery만큼 빨라지고, WithRateLimit의 간격보다 짧아지지는 않습니다.
+	for _, want := range []time.Duration{12500 * time.Microsecond, 6250 * time.Microsecond, 5 * time.Millisecond, 5 * time.Millisecond} {
+		get()
+		if got := interval(); got != want {
+			t.Errorf("after a successful request: interval = %v, want %v", got, want)
+		}
+	}
+}
+
+// -seen-db는 실행이 끝날 때 본 번호를 파일에 남겨서, 다음 실행에서는 그 뒤에 올라온 글만 new로 표시해야 합니다.
+func TestSeenDBAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.txt")
+	// main과 같이 실행마다 파일을 읽고, 결과를 받은 뒤에 add, save합니다.
+	run := func(pages fixtureFetcher) map[int]bool {
+		t.Helper()
+		seen, err := loadSeenSet(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(pages),
+			WithPostProcessor(seenProcessor(seen)),
+		)
+		got, err := s.Scrape(context.Background())
+		if err != nil {
+			t.Fatalf("Scrape: %v", err)
+		}
+		seen.add(got)
+		if err := seen.save(); err != nil {
+			t.Fatal(err)
+		}
+		isNew := map[int]bool{}
+		for _, page := range got {
+			isNew[page.pageNum] = page.isNew
+		}
+		return isNew
+	}
+
+	if got, want := run(fixtureFetcher{"": "normal.html", "1": "normal.html"}), map[int]bool{65: true, 64: true, 63: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first run: new = %v, want %v", got, want)
+	}
+	// 두 번째 실행에는 page 2(notices.html)가 생겼습니다. 공지(0)는 번호가 없어서 new가 아닙니다.
+	got := run(fixtureFetcher{"": "normal.html", "1": "normal.html", "2": "notices.html"})
+	if want := map[int]bool{65: false, 64: false, 63: false, 30: true, 29: true, 0: false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second run: new = %v, want %v", got, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "29-30\n63-65\n"; got != want {
+		t.Errorf("seen-db file = %q, want %q", got, want)
+	}
+}
+
+// -watermark는 실행마다 쓴 가장 큰 번호를 파일에 남기고, 다음 실행에서는 그보다 큰 번호의 글만 남겨야 합니다.
+func TestWatermarkAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watermark")
+	// main과 같이 실행마다 파일을 읽고, 결과를 받은 뒤에 mark를 올려서 씁니다.
+	run := func(pages fixtureFetcher) []int {
+		t.Helper()
+		mark, err := readWatermark(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := NewScraper(
+			WithBaseURL(fixtureBoardURL+"?p="),
+			WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+			WithFetcher(pages),
+			WithPostProcessor(watermarkFilter(&mark)),
+		)
+		got, err := s.Scrape(context.Background())
+		if err != nil {
+			t.Fatalf("Scrape: %v", err)
+		}
+		if err := writeWatermark(path, advanceWatermark(mark, got)); err != nil {
+			t.Fatal(err)
+		}
+		nums := []int{}
+		for _, page := range got {
+			nums = append(nums, page.pageNum)
+		}
+		return nums
+	}
+
+	for i, tt := range []struct {
+		pages    fixtureFetcher
+		want     []int
+		wantMark int
+	}{
+		{fixtureFetcher{"": "normal.html", "1": "normal.html"}, []int{63, 64, 65}, 65},
+		// gaps.html의 글은 모두 65보다 새 글입니다. (삭제된 94는 결과에서 빠집니다)
+		{fixtureFetcher{"": "gaps.html", "1": "gaps.html"}, []int{90, 93, 95}, 95},
+		// 이미 쓴 글만 있으면 아무것도 남지 않고 mark도 그대로입니다.
+		{fixtureFetcher{"": "normal.html", "1": "normal.html"}, []int{}, 95},
+	} {
+		if got := run(tt.pages); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("run %d: posts %v, want %v", i+1, got, tt.want)
+		}
+		if mark, err := readWatermark(path); err != nil || mark != tt.wantMark {
+			t.Errorf("run %d: watermark = %d, %v; want %d", i+1, mark, err, tt.wantMark)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 이전 실행에서 본 게시글 번호 목록 (-seen-db). 게시글 번호는 거의 연속이므로
+// 번호 하나하나 대신 연속된 구간(lo-hi)으로 저장해서, 게시글이 수백만 개여도 메모리와 파일 크기가 작게 유지됩니다.
+type seenSet struct {
+	path  string
+	spans []seenSpan // lo 오름차순, 서로 겹치거나 이어지지 않음
+}
+
+type seenSpan struct {
+	lo, hi int
+}
+
+// path에서 seen-set을 읽습니다. 파일이 없으면 빈 set으로 시작합니다.
+// 파일은 한 줄에 구간 하나("lo-hi" 또는 번호 하나)씩 적힌 텍스트입니다.
+func loadSeenSet(path string) (*seenSet, error) {
+	set := &seenSet{path: path}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return set, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	nums := []seenSpan{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		loText, hiText, isRange := strings.Cut(text, "-")
+		lo, err := strconv.Atoi(loText)
+		hi := lo
+		if err == nil && isRange {
+			hi, err = strconv.Atoi(hiText)
+		}
+		if err != nil || hi < lo {
+			return nil, fmt.Errorf("seen-db %s:%d: invalid range %q", path, line, text)
+		}
+		nums = append(nums, seenSpan{lo, hi})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	set.spans = mergeSpans(nums)
+	return set, nil
+}
+
+// 구간들을 정렬하고 겹치거나 이어지는 구간을 합칩니다.
+func mergeSpans(spans []seenSpan) []seenSpan {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].lo < spans[j].lo })
+
+	merged := []seenSpan{}
+	for _, span := range spans {
+		if last := len(merged) - 1; last >= 0 && span.lo <= merged[last].hi+1 {
+			if span.hi > merged[last].hi {
+				merged[last].hi = span.hi
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+func (s *seenSet) contains(num int) bool {
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].hi >= num })
+	return i < len(s.spans) && s.spans[i].lo <= num
+}
+
+// 게시글 번호들을 set에 추가합니다. 공지처럼 번호가 없는(0) 게시글은 건너뜁니다.
+func (s *seenSet) add(pages []pageInformation) {
+	spans := s.spans
+	for _, page := range pages {
+		if page.pageNum > 0 {
+			spans = append(spans, seenSpan{page.pageNum, page.pageNum})
+		}
+	}
+	s.spans = mergeSpans(spans)
+}
+
+// set을 path에 씁니다. 쓰다가 실패해도 이전 파일은 그대로 남습니다.
+func (s *seenSet) save() error {
+	return writeFileAtomic(s.path, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		for _, span := range s.spans {
+			if span.lo == span.hi {
+				fmt.Fprintln(bw, span.lo)
+			} else {
+				fmt.Fprintf(bw, "%d-%d\n", span.lo, span.hi)
+			}
+		}
+		return bw.Flush()
+	})
+}
+
+// -seen-db 옵션에서 사용하는 post processor. set에 없는 게시글을 new로 표시하고 그 수를 출력합니다.
+// set은 여기서 바꾸지 않습니다. 결과를 파일로 쓴 뒤에 main에서 add, save합니다.
+func seenProcessor(seen *seenSet) func([]pageInformation) ([]pageInformation, error) {
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		count := 0
+		for i := range pages {
+			if pages[i].pageNum > 0 && !seen.contains(pages[i].pageNum) {
+				pages[i].isNew = true
+				count++
+			}
+		}
+		fmt.Printf("seen-db: %d of %d posts are new\n", count, len(pages))
+		return pages, nil
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import "sort"
+
+// -server-sort 값별로 목록 URL에 붙일 query parameter. recent는 게시판 기본 순서라서 붙이지 않습니다.
+var serverSortParams = map[string]string{
+	"recent":    "",
+	"recommend": "sort=recommend",
+	"views":     "sort=hit",
+}
+
+func serverSortNames() []string {
+	names := []string{}
+	for name := range serverSortParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 게시판이 정렬해서 돌려주도록 목록 page URL에 정렬 parameter를 붙입니다. (...?sort=hit&p=3)
+// 마지막 page를 찾을 때는 게시글 번호가 필요하므로 정렬하지 않은 baseURL을 그대로 사용합니다.
+func WithServerSort(name string) Option {
+	return func(s *Scraper) {
+		s.serverSort = name
+	}
+}
+
+// 목록 앞에서부터 게시글 n개만 남깁니다. 필요한 만큼의 목록 page만 요청하고,
+// 번호가 없는 공지는 개수에 넣지 않습니다.
+func WithLimit(n int) Option {
+	return func(s *Scraper) {
+		s.limit = n
+	}
+}
+
+// 목록 순서로 정렬된 pages에서 번호가 있는 게시글을 n개까지 남깁니다.
+func limitPages(pages []pageInformation, n int) []pageInformation {
+	kept := pages[:0]
+	count := 0
+	for _, page := range pages {
+		if page.pageNum != 0 {
+			if count == n {
+				continue
+			}
+			count++
+		}
+		kept = append(kept, page)
+	}
+	return kept
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventSink는 수집한 게시글을 파일 대신(또는 파일과 함께) 다른 곳으로 내보냅니다. (message queue 등)
+// Publish는 목록 page 하나를 수집할 때마다 그 page의 게시글로 호출되므로, dedup과 post processor를 거치기 전의 게시글입니다.
+// 에러 없이 리턴했다면 전달이 끝난 것이어야 하고(at-least-once), 실패하면 Scrape가 중단됩니다.
+// Close는 남은 message를 모두 보내고 연결을 닫습니다.
+type EventSink interface {
+	Publish(ctx context.Context, pages []pageInformation) error
+	Close() error
+}
+
+// 수집한 게시글을 sink로도 내보냅니다. sink는 Scraper가 가져가서 Close할 때 닫습니다.
+func WithSink(sink EventSink) Option {
+	return func(s *Scraper) {
+		s.sink = sink
+	}
+}
+
+// -sink "<종류>:<대상>"의 종류별 생성 함수. 다른 backend는 build tag가 붙은 파일의 init에서 등록합니다. (sink_kafka.go)
+var sinkFactories = map[string]func(target string, flush sinkFlush) (EventSink, error){
+	"file": openFileSink,
+}
+
+// build tag 없이 빌드하면 등록되지 않는 sink와 필요한 tag
+var sinkBuildTags = map[string]string{
+	"kafka": "kafka",
+}
+
+func sinkNames() []string {
+	names := []string{}
+	for name := range sinkFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// -sink 값이 올바른지 확인합니다.
+func checkSink(spec string) error {
+	name, _, _ := strings.Cut(spec, ":")
+	if _, exists := sinkFactories[name]; exists {
+		return nil
+	}
+	if tag, exists := sinkBuildTags[name]; exists {
+		return fmt.Errorf("-sink %s requires a binary built with -tags %s", name, tag)
+	}
+	return fmt.Errorf("-sink %q is not supported (expected one of %s)", spec, strings.Join(sinkNames(), ", "))
+}
+
+func openSink(spec string, flush sinkFlush) (EventSink, error) {
+	if err := checkSink(spec); err != nil {
+		return nil, err
+	}
+	name, target, _ := strings.Cut(spec, ":")
+	return sinkFactories[name](target, flush)
+}
+
+// 버퍼에 쌓인 게시글을 디스크로 내보내는 주기. (-sink-flush-records, -sink-flush-interval)
+// 둘 다 0이면 Publish가 끝날 때마다 내보냅니다. 프로세스가 죽으면 마지막 주기의 게시글까지만 잃습니다.
+type sinkFlush struct {
+	records  int
+	interval time.Duration
+}
+
+// 게시글 하나당 JSON 한 줄씩 파일 끝에 덧붙입니다. (-sink file:posts.ndjson, tail -f로 볼 수 있음)
+// 쓰기는 버퍼에 모았다가 flush 주기마다 파일에 쓰고 fsync합니다. interval flush는 별도 goroutine에서 하므로
+// 버퍼는 mu로 보호합니다.
+type ndjsonSink struct {
+	mu      sync.Mutex
+	w       *bufio.Writer
+	file    *os.File
+	flush   sinkFlush
+	pending int // 마지막 flush 뒤에 쓴 게시글 수
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func openFileSink(path string, flush sinkFlush) (EventSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-sink file: expected file:<path>")
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	sink := &ndjsonSink{w: bufio.NewWriter(file), file: file, flush: flush}
+	if flush.interval > 0 {
+		sink.stop = make(chan struct{})
+		sink.done = make(chan struct{})
+		go sink.flushEvery(flush.interval)
+	}
+	return sink, nil
+}
+
+func (s *ndjsonSink) Publish(ctx context.Context, pages []pageInformation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(s.w)
+	enc.SetEscapeHTML(false)
+	for _, page := range pages {
+		if err := enc.Encode(page); err != nil {
+			return err
+		}
+		s.pending++
+		if s.flush.records > 0 && s.pending >= s.flush.records {
+			if err := s.flushLocked(); err != nil {
+				return err
+			}
+		}
+	}
+	if s.flush.records == 0 && s.flush.interval == 0 {
+		return s.flushLocked()
+	}
+	return nil
+}
+
+// 버퍼를 파일에 쓰고 fsync합니다. s.mu를 잡고 호출해야 합니다.
+func (s *ndjsonSink) flushLocked() error {
+	if s.pending == 0 {
+		return nil
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	s.pending = 0
+	return s.file.Sync()
+}
+
+func (s *ndjsonSink) flushEvery(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if err := s.flushLocked(); err != nil {
+				log.Printf("sink: flush: %v\n", err)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *ndjsonSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.flushLocked()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (c Config) sinkFlush() sinkFlush {
+	return sinkFlush{records: c.SinkFlushRecords, interval: time.Duration(c.SinkFlushInterval)}
+}
@@ -0,0 +1,66 @@
+//go:build kafka
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func init() {
+	sinkFactories["kafka"] = newKafkaSink
+}
+
+// -sink kafka:broker1:9092,broker2:9092/topic
+// 게시글 하나를 JSON message 하나로 보내고, key는 게시글 번호(공지는 링크)입니다.
+// 모든 replica가 받았다는 응답을 기다린 뒤 Publish가 리턴하므로 at-least-once로 전달됩니다.
+// 버퍼에 남기는 게시글이 없으므로 sinkFlush는 쓰지 않습니다.
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+func newKafkaSink(target string, _ sinkFlush) (EventSink, error) {
+	brokers, topic, found := strings.Cut(target, "/")
+	if !found || brokers == "" || topic == "" {
+		return nil, fmt.Errorf("-sink kafka:%s: expected kafka:host:port[,host:port...]/topic", target)
+	}
+
+	return &kafkaSink{writer: &kafka.Writer{
+		Addr:         kafka.TCP(strings.Split(brokers, ",")...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchSize:    100,
+		BatchTimeout: 100 * time.Millisecond,
+		MaxAttempts:  10,
+	}}, nil
+}
+
+func (s *kafkaSink) Publish(ctx context.Context, pages []pageInformation) error {
+	messages := []kafka.Message{}
+	for _, page := range pages {
+		value, err := json.Marshal(page)
+		if err != nil {
+			return err
+		}
+		key := page.link
+		if page.pageNum != 0 {
+			key = strconv.Itoa(page.pageNum)
+		}
+		messages = append(messages, kafka.Message{Key: []byte(key), Value: value})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return s.writer.WriteMessages(ctx, messages...)
+}
+
+func (s *kafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 받은 목록 page의 HTML을 dir에 page 번호로 저장합니다. (-snapshot-html, 12.html.gz)
+// 저장하는 HTML은 goquery가 파싱한 문서를 다시 쓴 것이라 공백이나 속성 순서는 원본과 다를 수 있지만,
+// 다시 파싱하면 같은 결과가 나옵니다.
+func WithSnapshotDir(dir string) Option {
+	return func(s *Scraper) {
+		s.snapshotDir = dir
+	}
+}
+
+func snapshotName(pageNum int) string {
+	return fmt.Sprintf("%d.html.gz", pageNum)
+}
+
+// 게시판에 요청하지 않고 -snapshot-html로 저장한 dir의 HTML을 목록 page로 사용합니다. (-from-snapshots)
+// 파싱, 필터, 출력은 요청해서 받은 page와 똑같이 거치므로, selector나 출력 옵션을 바꿔서 같은 page를 다시 처리할 수 있습니다.
+// 마지막 page는 저장된 가장 큰 page 번호이고, -from, -to, -max-pages도 그 안에서 적용됩니다.
+func WithSnapshotSource(dir string) Option {
+	return func(s *Scraper) {
+		s.snapshotSource = dir
+	}
+}
+
+// snapshot이 있는 page 번호를 오름차순으로 리턴합니다.
+func listSnapshots(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("-from-snapshots: %w", err)
+	}
+	pageNums := []int{}
+	for _, entry := range entries {
+		n, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".html.gz"))
+		if err != nil || n < 1 || entry.Name() != snapshotName(n) {
+			continue
+		}
+		pageNums = append(pageNums, n)
+	}
+	sort.Ints(pageNums)
+	if len(pageNums) == 0 {
+		return nil, fmt.Errorf("-from-snapshots: no page snapshots (1.html.gz, 2.html.gz, ...) in %s", dir)
+	}
+	return pageNums, nil
+}
+
+// -from-snapshots에서 파싱할 page 번호. pageRange에 들어가면서 snapshot이 있는 page만 고릅니다.
+func (s *Scraper) snapshotPages() ([]int, error) {
+	available, err := listSnapshots(s.snapshotSource)
+	if err != nil {
+		return nil, err
+	}
+	s.lastPage = available[len(available)-1]
+	fmt.Printf("%d page snapshots found in %s\n", len(available), s.snapshotSource)
+
+	exists := map[int]bool{}
+	for _, n := range available {
+		exists[n] = true
+	}
+	pageNums := []int{}
+	for _, n := range s.pageRange(s.lastPage) {
+		if exists[n] {
+			pageNums = append(pageNums, n)
+		}
+	}
+	return pageNums, nil
+}
+
+// snapshot 하나를 읽어서 게시글로 바꿉니다. 링크는 저장할 때의 page URL 기준으로, 날짜는 그 실행의 기준 시간으로 읽습니다.
+func (s *Scraper) readSnapshotPage(pageNum int) ([]pageInformation, []parseWarning, error) {
+	path := filepath.Join(s.snapshotSource, snapshotName(pageNum))
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	doc, err := goquery.NewDocumentFromReader(zr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	url, now := zr.Comment, zr.ModTime
+	if url == "" {
+		url = s.PageURL(pageNum)
+	}
+	if now.IsZero() {
+		now = s.referenceTime()
+	}
+	pages, warnings := s.parseListing(doc, url, now)
+	warnings, err = s.checkViewColumn(doc, url, warnings)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pages, warnings, nil
+}
+
+// doc을 gzip으로 압축해서 저장합니다. 나중에 같은 URL 기준으로 링크를 풀고 같은 기준 시간으로 "5분 전" 같은 날짜를 읽을 수 있도록,
+// gzip header의 Comment에 URL을, ModTime에 수집을 시작한 시간을 기록합니다.
+func (s *Scraper) saveSnapshot(pageNum int, url string, doc *goquery.Document) error {
+	html, err := goquery.OuterHtml(doc.Selection)
+	if err != nil {
+		return fmt.Errorf("snapshot of page %d: %w", pageNum, err)
+	}
+	if err := os.MkdirAll(s.snapshotDir, 0755); err != nil {
+		return fmt.Errorf("snapshot of page %d: %w", pageNum, err)
+	}
+
+	name := snapshotName(pageNum)
+	err = writeFileAtomic(filepath.Join(s.snapshotDir, name), func(w io.Writer) error {
+		zw := gzip.NewWriter(w)
+		zw.Name = name[:len(name)-len(".gz")]
+		zw.Comment = url
+		zw.ModTime = s.referenceTime()
+		if _, err := io.WriteString(zw, html); err != nil {
+			return err
+		}
+		return zw.Close()
+	})
+	if err != nil {
+		return fmt.Errorf("snapshot of page %d: %w", pageNum, err)
+	}
+	return nil
+}
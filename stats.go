@@ -0,0 +1,39 @@
+package main
+
+import "fmt"
+
+// 수집하면서 바로 쌓는 합계 (-stats). 결과를 다시 순회하지 않도록 collector가 page 결과를 받을 때마다 더합니다.
+// collector goroutine 하나만 접근하므로 lock이 필요 없고, 합과 최댓값만 사용하므로 page가 끝나는 순서와 상관없이 결과가 같습니다.
+type scrapeStats struct {
+	pages int // 수집에 성공한 목록 page 수
+	posts int
+
+	views     int
+	maxViews  int
+	comments  int
+	recommend int
+
+	maxRecommend int
+}
+
+func (st *scrapeStats) add(page pageInformation) {
+	st.posts++
+	st.views += page.view
+	st.comments += page.comments
+	st.recommend += page.recommend
+	if page.view > st.maxViews {
+		st.maxViews = page.view
+	}
+	if page.recommend > st.maxRecommend {
+		st.maxRecommend = page.recommend
+	}
+}
+
+func (st scrapeStats) String() string {
+	avgViews := 0.0
+	if st.posts > 0 {
+		avgViews = float64(st.views) / float64(st.posts)
+	}
+	return fmt.Sprintf("stats: %d posts from %d pages, views total %d (avg %.1f, max %d), comments total %d, recommend total %d (max %d)",
+		st.posts, st.pages, st.views, avgViews, st.maxViews, st.comments, st.recommend, st.maxRecommend)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+	"golang.org/x/text/width"
+)
+
+// 터미널의 폭을 알아내지 못했고 COLUMNS 환경 변수도 없을 때 사용하는 폭
+const defaultTableWidth = 80
+
+// 줄여도 이 폭보다는 좁게 만들지 않습니다.
+const minColumnWidth = 8
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiReset = "\x1b[0m"
+)
+
+// -color 값
+var colorModes = []string{"auto", "always", "never"}
+
+// 설정과 stdout에 따라 색을 쓸지 정합니다. always, never가 NO_COLOR 환경 변수보다 우선합니다.
+func (c Config) useColor(isTerminal bool) bool {
+	switch {
+	case c.NoColor || c.Color == "never":
+		return false
+	case c.Color == "always":
+		return true
+	}
+	return isTerminal && os.Getenv("NO_COLOR") == ""
+}
+
+// -pretty-table: 결과를 컬럼을 맞춘 표로 stdout에 출력합니다. stdout이 터미널이면 그 폭에 맞추고, -color에 따라 헤더를 굵게 표시합니다.
+// 파일이나 pipe로 출력할 때는 -table-width를 주지 않으면 자르지 않습니다.
+func printTable(pages []pageInformation, cfg Config) {
+	tableWidth, color := terminalInfo(cfg)
+	shown := pages
+	if cfg.Top > 0 && len(shown) > cfg.Top {
+		shown = shown[:cfg.Top]
+	}
+	writeTable(os.Stdout, shown, cfg, tableWidth, color)
+	if len(shown) < len(pages) {
+		fmt.Printf("(%d of %d rows)\n", len(shown), len(pages))
+	}
+}
+
+// stdout에 맞는 표의 폭과 색을 쓸지를 리턴합니다. 색은 cfg.useColor로 정합니다.
+func terminalInfo(cfg Config) (tableWidth int, color bool) {
+	fd := int(os.Stdout.Fd())
+	isTerminal := term.IsTerminal(fd)
+	size := func() (int, error) {
+		w, _, err := term.GetSize(fd)
+		return w, err
+	}
+	return tableWidthFor(cfg, isTerminal, size, os.Getenv("COLUMNS")), cfg.useColor(isTerminal)
+}
+
+// -table-width를 주면 그 폭을 씁니다. 아니면 터미널일 때 그 폭을, 폭을 알아내지 못하면 COLUMNS 환경 변수나
+// defaultTableWidth를 쓰고, 터미널이 아니면 0(폭 제한 없음)을 리턴합니다.
+func tableWidthFor(cfg Config, isTerminal bool, size func() (int, error), columns string) int {
+	if cfg.TableWidth > 0 {
+		return cfg.TableWidth
+	}
+	if !isTerminal {
+		return 0
+	}
+	if w, err := size(); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(strings.TrimSpace(columns)); err == nil && w > 0 {
+		return w
+	}
+	return defaultTableWidth
+}
+
+func writeTable(w io.Writer, pages []pageInformation, cfg Config, tableWidth int, color bool) {
+	fields := cfg.csvFields()
+
+	rows := [][]string{{}}
+	for _, f := range fields {
+		rows[0] = append(rows[0], cfg.csvHeader(f))
+	}
+	for _, page := range pages {
+		row := []string{}
+		for _, f := range fields {
+			// 줄바꿈이 들어 있으면 표가 깨지므로 한 줄로 합칩니다.
+			row = append(row, strings.Join(strings.Fields(f.value(page)), " "))
+		}
+		rows = append(rows, row)
+	}
+	writeRows(w, rows, tableWidth, color)
+}
+
+// rows[0]을 헤더로 해서 컬럼을 맞춘 표를 씁니다. 폭이 tableWidth를 넘으면 넓은 컬럼부터 잘라냅니다. (0이면 자르지 않음)
+func writeRows(w io.Writer, rows [][]string, tableWidth int, color bool) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+	if tableWidth > 0 {
+		fitWidths(widths, tableWidth-2*(len(widths)-1))
+	}
+
+	for r, row := range rows {
+		cells := []string{}
+		for i, cell := range row {
+			cells = append(cells, pad(truncate(cell, widths[i]), widths[i]))
+		}
+		line := strings.TrimRight(strings.Join(cells, "  "), " ")
+		if r == 0 && color {
+			line = ansiBold + line + ansiReset
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// 전체 폭이 available을 넘으면 넘는 만큼 가장 넓은 컬럼(보통 제목, 링크)부터 줄입니다.
+func fitWidths(widths []int, available int) {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	for total > available {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// 터미널에서 차지하는 폭. 한글처럼 넓은 문자는 2칸으로 셉니다.
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// 폭이 n을 넘으면 잘라서 끝에 …을 붙입니다.
+func truncate(s string, n int) string {
+	if displayWidth(s) <= n {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		if used+runeWidth(r) > n-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += runeWidth(r)
+	}
+	return b.String() + "…"
+}
+
+func pad(s string, n int) string {
+	return s + strings.Repeat(" ", max(0, n-displayWidth(s)))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// -throttle-on-error의 설정
+type throttleSettings struct {
+	threshold float64 // 최근 window개 요청 중 실패 비율이 이보다 높으면 속도를 줄입니다.
+	window    int
+	factor    float64 // 속도를 줄일 때 곱하는 비율 (0~1)
+	recovery  float64 // 요청이 성공할 때마다 곱하는 비율 (1보다 큼). 원래 속도를 넘지는 않습니다.
+	minRate   float64 // 이보다 느리게는 줄이지 않습니다.
+}
+
+// 모든 worker의 최근 요청 결과를 모아서, 실패가 많아지면 공유하는 rateLimiter의 속도를 줄이고
+// 다시 성공하면 원래 속도(WithRateLimit)까지 천천히 되돌립니다.
+// 요청 하나하나의 재시도와 달리 일부 서버 장애나 soft-block이 지나갈 때까지 실행 전체를 느리게 합니다.
+type throttleController struct {
+	mu       sync.Mutex
+	limiter  *rateLimiter
+	settings throttleSettings
+
+	baseRate float64
+	recent   []bool // 최근 요청의 실패 여부 (ring buffer)
+	next     int
+	filled   int
+}
+
+func (t *throttleController) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.baseRate = t.limiter.Rate()
+	t.recent = make([]bool, t.settings.window)
+	t.next, t.filled = 0, 0
+}
+
+func (t *throttleController) observe(failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.recent[t.next] = failed
+	t.next = (t.next + 1) % len(t.recent)
+	t.filled = min(t.filled+1, len(t.recent))
+
+	rate := t.limiter.Rate()
+	if !failed {
+		if rate < t.baseRate {
+			next := min(rate*t.settings.recovery, t.baseRate)
+			t.limiter.SetRate(next)
+			if next == t.baseRate {
+				fmt.Printf("throttle: recovered to %.2f req/s\n", next)
+			}
+		}
+		return
+	}
+
+	failures := 0
+	for _, f := range t.recent[:t.filled] {
+		if f {
+			failures++
+		}
+	}
+	// 요청이 몇 개 안 되었을 때 한두 번의 실패로 속도를 줄이지 않도록, window의 절반은 채워진 뒤에 판단합니다.
+	errorRate := float64(failures) / float64(t.filled)
+	if t.filled*2 < len(t.recent) || errorRate <= t.settings.threshold {
+		return
+	}
+
+	next := max(rate*t.settings.factor, t.settings.minRate)
+	if next < rate {
+		t.limiter.SetRate(next)
+		fmt.Printf("throttle: %.0f%% of the last %d requests failed, slowing down to %.2f req/s\n", errorRate*100, t.filled, next)
+	}
+	// 줄인 속도에서 다시 판단하도록 지난 결과는 버립니다.
+	t.filled = 0
+}
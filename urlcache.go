@@ -0,0 +1,87 @@
+package main
+
+import (
+	"container/list"
+	neturl "net/url"
+	"strings"
+	"sync"
+)
+
+// resolveURL의 결과를 기억해둘 개수. 같은 이미지 서버의 경로나 아이콘처럼 반복되는 상대 경로가 많습니다.
+const resolvedURLCacheSize = 1024
+
+// 최근에 사용한 순서로 최대 size개를 기억하는 cache (LRU). 여러 worker가 같이 사용합니다.
+type urlCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // 앞쪽이 최근에 사용한 항목
+	items map[string]*list.Element
+}
+
+type urlCacheEntry struct {
+	key, value string
+}
+
+func newURLCache(size int) *urlCache {
+	return &urlCache{size: size, order: list.New(), items: map[string]*list.Element{}}
+}
+
+func (c *urlCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, exists := c.items[key]
+	if !exists {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*urlCacheEntry).value, true
+}
+
+func (c *urlCache) add(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, exists := c.items[key]; exists {
+		e.Value.(*urlCacheEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.order.PushFront(&urlCacheEntry{key, value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*urlCacheEntry).key)
+	}
+}
+
+var resolvedURLs = newURLCache(resolvedURLCacheSize)
+
+// ref를 base 기준의 절대 URL로 바꿉니다. base가 없거나 ref를 해석할 수 없으면 ref를 그대로 리턴합니다.
+//
+// 목록 page의 URL은 page마다 query만 다르고, 경로가 있는 ref의 결과는 base의 query와 상관이 없으므로
+// base의 query를 뺀 부분과 ref로 결과를 기억해두고 다른 page에서도 다시 사용합니다.
+// query나 #fragment만 있는 ref는 base의 query에 따라 결과가 달라지므로 기억하지 않습니다.
+func resolveURL(base *neturl.URL, ref string) string {
+	if base == nil {
+		return ref
+	}
+	ref = strings.TrimSpace(ref)
+
+	cacheable := ref != "" && ref[0] != '?' && ref[0] != '#'
+	key := ""
+	if cacheable {
+		key = base.Scheme + "://" + base.Host + base.EscapedPath() + "\x00" + ref
+		if resolved, exists := resolvedURLs.get(key); exists {
+			return resolved
+		}
+	}
+
+	u, err := neturl.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	resolved := base.ResolveReference(u).String()
+	if cacheable {
+		resolvedURLs.add(key, resolved)
+	}
+	return resolved
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// -validate-output에서 보고하는 최대 문제 수
+const maxOutputProblems = 5
+
+// 방금 쓴 결과 파일을 다시 읽어서 pages와 같은지 확인합니다. (-validate-output)
+// CSV는 헤더와 모든 값이, JSON과 NDJSON은 게시글마다 JSON 표현이 같아야 합니다.
+// 링크와 (-keep-empty가 아니면) 제목은 비어 있으면 안 됩니다.
+func validateOutput(path, format string, pages []pageInformation, cfg Config) error {
+	var problems []string
+	var err error
+	if format == "csv" {
+		problems, err = validateCSVOutput(path, pages, cfg)
+	} else {
+		problems, err = validateJSONOutput(path, format, pages)
+	}
+	if err != nil {
+		return fmt.Errorf("validate-output %s: %w", path, err)
+	}
+
+	for i, page := range pages {
+		if page.link == "" {
+			problems = append(problems, fmt.Sprintf("row %d has no link", i+1))
+		}
+		if page.title == "" && !cfg.KeepEmpty {
+			problems = append(problems, fmt.Sprintf("row %d has no title", i+1))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	more := ""
+	if len(problems) > maxOutputProblems {
+		more = fmt.Sprintf("\n  ... and %d more", len(problems)-maxOutputProblems)
+		problems = problems[:maxOutputProblems]
+	}
+	return fmt.Errorf("validate-output %s does not match the scraped rows:\n  %s%s", path, strings.Join(problems, "\n  "), more)
+}
+
+func validateCSVOutput(path string, pages []pageInformation, cfg Config) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := newCSVReader(decodeExport(file, cfg.Encoding)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := cfg.csvFields()
+	want := [][]string{{}}
+	for _, f := range fields {
+		want[0] = append(want[0], cfg.csvHeader(f))
+	}
+	for _, page := range pages {
+		want = append(want, cfg.csvRecord(page, fields))
+	}
+	if len(records) != len(want) {
+		return []string{fmt.Sprintf("read %d rows, wrote %d", len(records)-1, len(pages))}, nil
+	}
+
+	problems := []string{}
+	for i := range want {
+		if len(records[i]) != len(want[i]) {
+			problems = append(problems, fmt.Sprintf("row %d has %d columns, want %d", i, len(records[i]), len(want[i])))
+			continue
+		}
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				problems = append(problems, fmt.Sprintf("row %d %s = %q, want %q", i, want[0][j], records[i][j], want[i][j]))
+			}
+		}
+	}
+	return problems, nil
+}
+
+func validateJSONOutput(path, format string, pages []pageInformation) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var read []pageInformation
+	if format == "json" {
+		err = json.NewDecoder(file).Decode(&read)
+	} else {
+		read, err = readNDJSON(path, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(read) != len(pages) {
+		return []string{fmt.Sprintf("read %d rows, wrote %d", len(read), len(pages))}, nil
+	}
+
+	problems := []string{}
+	for i := range pages {
+		want, _ := json.Marshal(pages[i])
+		got, _ := json.Marshal(read[i])
+		if !bytes.Equal(got, want) {
+			problems = append(problems, fmt.Sprintf("row %d = %s, want %s", i+1, got, want))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+// 빌드할 때 -ldflags로 덮어씁니다.
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+func versionString() string {
+	return fmt.Sprintf("example-webscraper %s (commit %s, built %s)", version, commit, buildDate)
+}
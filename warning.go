@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+)
+
+// 목록 page를 파싱하다가 건너뛰거나 이상하게 읽은 행. row가 -1이면 page 전체에 대한 경고입니다.
+type parseWarning struct {
+	listPage int
+	row      int
+	reason   string
+}
+
+func (w parseWarning) String() string {
+	if w.row < 0 {
+		return fmt.Sprintf("page %d: %s", w.listPage, w.reason)
+	}
+	return fmt.Sprintf("page %d row %d: %s", w.listPage, w.row, w.reason)
+}
+
+func sortWarnings(warnings []parseWarning) {
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].listPage != warnings[j].listPage {
+			return warnings[i].listPage < warnings[j].listPage
+		}
+		return warnings[i].row < warnings[j].row
+	})
+}
+
+// 한 번에 출력할 parse 경고 수
+const maxLoggedWarnings = 10
+
+func logParseWarnings(warnings []parseWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+	log.Printf("%d parse warnings\n", len(warnings))
+	for i, w := range warnings {
+		if i == maxLoggedWarnings {
+			log.Printf("  ... and %d more\n", len(warnings)-maxLoggedWarnings)
+			break
+		}
+		log.Println("  " + w.String())
+	}
+}
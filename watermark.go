@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// -watermark 파일에서 지금까지 쓴 가장 큰 게시글 번호를 읽습니다. 파일이 없으면(첫 실행) 0을 리턴합니다.
+func readWatermark(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return 0, nil
+	}
+	mark, err := strconv.Atoi(text)
+	if err != nil || mark < 0 {
+		return 0, fmt.Errorf("watermark %s: %q is not a post number", path, text)
+	}
+	return mark, nil
+}
+
+func writeWatermark(path string, mark int) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, mark)
+		return err
+	})
+}
+
+// -watermark 옵션에서 사용하는 post processor. 번호가 *mark보다 큰 게시글만 남깁니다.
+// -interval로 반복할 때 main이 실행마다 mark를 올리므로 포인터로 받습니다. 번호가 없는 공지는 새 글이 아니므로 빠집니다.
+func watermarkFilter(mark *int) func([]pageInformation) ([]pageInformation, error) {
+	return func(pages []pageInformation) ([]pageInformation, error) {
+		kept := []pageInformation{}
+		for _, page := range pages {
+			if page.pageNum > *mark {
+				kept = append(kept, page)
+			}
+		}
+		fmt.Printf("watermark %d: %d of %d posts are newer\n", *mark, len(kept), len(pages))
+		return kept, nil
+	}
+}
+
+// 결과에서 가장 큰 게시글 번호와 mark 중 큰 값을 리턴합니다.
+func advanceWatermark(mark int, pages []pageInformation) int {
+	for _, page := range pages {
+		if page.pageNum > mark {
+			mark = page.pageNum
+		}
+	}
+	return mark
+}